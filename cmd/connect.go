@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"strings"
 	"sync"
 	"syscall"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -19,6 +21,37 @@ import (
 
 var noTUI bool // Variable to store the value of the --no-tui flag
 
+var leaderElection bool     // Variable to store the value of the --leader-election flag
+var readOnly bool           // Variable to store the value of the --read-only flag
+var instanceLockPath string // Variable to store the value of the --lock-file flag
+
+var forceSwitch bool // Variable to store the value of the --force flag
+
+var enableKubectlPane bool // Variable to store the value of the --enable-kubectl-pane flag
+
+var alertRules = tui.DefaultAlertRules() // Alert thresholds, set via the --alert-* flags
+
+var logBufferLines int // Variable to store the value of the --log-buffer-lines flag
+var logBufferBytes int // Variable to store the value of the --log-buffer-bytes flag
+
+var healthPauseDuration time.Duration // Variable to store the value of the --health-pause-duration flag
+
+var maintenanceDuration time.Duration // Variable to store the value of the --maintenance-duration flag
+
+var lowBandwidth string // Variable to store the value of the --low-bandwidth flag
+
+var childEnvAllow []string // Variable to store the values of the --child-env-allow flag
+var childEnvSet []string   // Variable to store the values of the --child-env-set flag
+var childEnvInherit bool   // Variable to store the value of the --child-env-inherit flag
+
+var bindAddressEntries []string // Variable to store the values of the --bind-address flag
+
+var skipPreflight bool // Variable to store the value of the --skip-preflight flag
+
+var isolatedKubeconfig bool       // Variable to store the value of the --isolated-kubeconfig flag
+var isolatedKubeconfigPath string // Variable to store the value of the --isolated-kubeconfig-path flag
+var temporaryKubeconfig bool      // Variable to store the value of the --temporary-kubeconfig flag
+
 // connectCmdDef defines the connect command structure
 var connectCmdDef = &cobra.Command{
 	Use:   "connect <management-cluster> [workload-cluster-shortname]",
@@ -46,6 +79,48 @@ It can run in two modes:
    - Useful for scripting or when a TUI is not desired. Port-forwards continue to run
      until the 'envctl' process initiated by 'connect --no-tui' is terminated (e.g., Ctrl+C).
 
+Shared instances (using --leader-election flag):
+   - On a shared jump host only one envctl instance should own the port-forwards.
+   - The first instance acquires a lock file and becomes the leader. Further TUI instances
+     attach in read-only mode, showing cluster health without starting or changing anything.
+   - In --no-tui mode a non-leader instance exits with an error naming the current leader.
+
+Read-only mode (using --read-only flag):
+   - Starts the TUI without logging in, switching contexts or starting port-forwards,
+     e.g. for demos or onboarding. Cluster health is still shown using existing contexts.
+   - Actions that change anything (new connection, restarts, context switch, kubectl pane)
+     are refused with a message in the activity log.
+
+Child process environment:
+   - tsh and kubectl only receive an allowlist of environment variables (PATH, HOME, locale,
+     KUBECONFIG, TELEPORT_*, proxies, ...), so tokens in your environment do not leak to them.
+   - --child-env-allow adds variables or PREFIX* patterns, --child-env-set injects NAME=VALUE;
+     prefix either with "tsh:" or "kubectl:" to apply it to that command only.
+   - --child-env-inherit passes the complete environment as before (deprecated).
+
+Isolated kubeconfig (using --isolated-kubeconfig flag):
+   - envctl writes its contexts to its own kubeconfig file instead of the global one,
+     so the current-context seen by other terminals is left untouched.
+   - Run 'export KUBECONFIG=<path>' in a shell to use the envctl contexts there.
+   - --temporary-kubeconfig uses a new kubeconfig for this instance only, removed on exit.
+
+Low-bandwidth mode (using --low-bandwidth flag):
+   - Over SSH (SSH_CONNECTION is set) or on basic terminals the TUI redraws at most twice a second
+     and uses ASCII borders, no backgrounds and 16 colors, which keeps it usable over slow links.
+   - --low-bandwidth=on or off overrides the detection.
+
+Bind addresses (using --bind-address flag):
+   - Port-forwards listen on 127.0.0.1 by default. --bind-address sets other addresses,
+     e.g. 0.0.0.0 for use from a VM, or ::1; repeat it to listen on several (dual-stack).
+   - Prefix an address with a port-forward as NAME=ADDRESS to apply it to that one only,
+     e.g. "Grafana=0.0.0.0" or "Grafana (WC)=0.0.0.0".
+   - Non-loopback addresses are reachable from other hosts without authentication; envctl warns about them.
+
+Preflight checks:
+   - Before logging in, envctl checks that the local ports of its port-forwards are free,
+     that the open file limit is high enough and that there is some free disk space.
+   - Busy ports and a far too low file limit abort the start; --skip-preflight starts anyway.
+
 Arguments:
   <management-cluster>: (Required) The name of the Giant Swarm management cluster (e.g., "myinstallation", "mycluster").
   [workload-cluster-shortname]: (Optional) The *short* name of the workload cluster (e.g., "myworkloadcluster" for "myinstallation-myworkloadcluster", "customerprod" for "mycluster-customerprod").`,
@@ -60,6 +135,133 @@ Arguments:
 			fullWorkloadClusterName = managementCluster + "-" + shortWorkloadClusterName
 		}
 
+		// --- Child Process Environment ---
+		// Must happen before any tsh or kubectl invocation.
+		if childEnvInherit {
+			fmt.Fprintln(os.Stderr, "Warning: --child-env-inherit passes envctl's complete environment to tsh and kubectl; it will be removed in a future release.")
+		}
+		envPolicy := utils.ChildEnvPolicy{
+			InheritAll: childEnvInherit,
+			Allow:      append(append([]string{}, utils.DefaultChildEnvAllowlist...), childEnvAllow...),
+			Set:        childEnvSet,
+		}
+		if err := utils.SetChildEnvPolicy(envPolicy); err != nil {
+			return err
+		}
+
+		// --- Bind Addresses ---
+		if err := utils.SetBindAddresses(bindAddressEntries); err != nil {
+			return err
+		}
+		if exposed := utils.NonLoopbackBindAddresses(); len(exposed) > 0 {
+			fmt.Fprintf(os.Stderr, "Warning: port-forwards listen on %s and have no authentication; anyone who can reach this host can use them.\n", strings.Join(exposed, ", "))
+		}
+
+		// --- Kubeconfig Isolation ---
+		// Must happen before any tsh or kubectl invocation so they all use the same file.
+		if isolatedKubeconfig || temporaryKubeconfig {
+			var kubeconfig *utils.IsolatedKubeconfig
+			var err error
+			if temporaryKubeconfig {
+				isolatedKubeconfig = true
+				kubeconfig, err = utils.UseTemporaryKubeconfig()
+			} else {
+				kubeconfig, err = utils.UseIsolatedKubeconfig(isolatedKubeconfigPath)
+			}
+			if err != nil {
+				return err
+			}
+			defer func() {
+				if err := kubeconfig.Close(); err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+				}
+			}()
+			fmt.Printf("Using isolated kubeconfig: %s\n", kubeconfig.Path)
+		}
+
+		lowBandwidthEnabled := tui.DetectLowBandwidth(os.Getenv)
+		switch lowBandwidth {
+		case "auto": // Keep the detected value
+		case "on":
+			lowBandwidthEnabled = true
+		case "off":
+			lowBandwidthEnabled = false
+		default:
+			return fmt.Errorf("invalid --low-bandwidth value %q, expected auto, on or off", lowBandwidth)
+		}
+
+		if readOnly && noTUI {
+			return fmt.Errorf("--read-only cannot be combined with --no-tui: without the TUI there is nothing to show")
+		}
+
+		// --- Leader Election ---
+		tuiOpts := tui.Options{
+			AutoConfirm:         forceSwitch,
+			EnableKubectlPane:   enableKubectlPane,
+			AlertRules:          alertRules,
+			LogBufferLines:      logBufferLines,
+			LogBufferBytes:      logBufferBytes,
+			HealthPauseDuration: healthPauseDuration,
+			MaintenanceDuration: maintenanceDuration,
+			LowBandwidth:        lowBandwidthEnabled,
+		}
+		if readOnly {
+			tuiOpts.ReadOnly = true
+			tuiOpts.ReadOnlyReason = "started with --read-only"
+		} else if leaderElection {
+			lock, holder, err := utils.AcquireInstanceLock(instanceLockPath)
+			if err != nil {
+				return fmt.Errorf("leader election failed: %w", err)
+			}
+			if lock == nil {
+				if noTUI {
+					return fmt.Errorf("another envctl instance is the leader: %s", holder)
+				}
+				fmt.Printf("Another envctl instance is the leader: %s\n", holder)
+				fmt.Println("Starting TUI in read-only mode...")
+				tuiOpts.ReadOnly = true
+				tuiOpts.ReadOnlyReason = "port-forwards are managed by " + holder.String()
+			} else {
+				defer func() {
+					if err := lock.Release(); err != nil {
+						fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+					}
+				}()
+			}
+		}
+
+		teleportContextToUse := "teleport.giantswarm.io-" + managementCluster
+		if fullWorkloadClusterName != "" {
+			teleportContextToUse = "teleport.giantswarm.io-" + fullWorkloadClusterName
+		}
+
+		// A read-only instance must not log in or switch the shared kubectl context.
+		if tuiOpts.ReadOnly {
+			initialModel := tui.InitialModel(managementCluster, fullWorkloadClusterName, teleportContextToUse, tuiOpts)
+			p := tea.NewProgram(initialModel, tea.WithAltScreen(), tea.WithMouseAllMotion())
+			if _, err := p.Run(); err != nil {
+				fmt.Fprintf(os.Stderr, "Error running TUI: %v\n", err)
+				return err
+			}
+			return nil
+		}
+
+		// --- Preflight ---
+		// Catch busy ports and low limits now instead of as port-forward errors once the TUI is up.
+		var wcKubeContext string
+		if fullWorkloadClusterName != "" {
+			wcKubeContext = "teleport.giantswarm.io-" + fullWorkloadClusterName
+		}
+		specs := utils.PortForwardsForConnection("teleport.giantswarm.io-"+managementCluster, wcKubeContext, utils.DefaultPortForwardTemplates)
+		preflightFailed := false
+		for _, finding := range utils.RunPreflight(specs) {
+			fmt.Fprintf(os.Stderr, "Preflight %s\n", finding)
+			preflightFailed = preflightFailed || finding.Error
+		}
+		if preflightFailed && !skipPreflight {
+			return fmt.Errorf("preflight checks failed; fix the errors above or use --skip-preflight to start anyway")
+		}
+
 		// --- Login Logic ---
 		fmt.Println("--- Kubernetes Login ---")
 
@@ -76,8 +278,6 @@ Arguments:
 			return fmt.Errorf("failed to log into management cluster '%s': %w", managementCluster, err)
 		}
 
-		teleportContextToUse := "teleport.giantswarm.io-" + managementCluster
-
 		if fullWorkloadClusterName != "" {
 			wcLoginStdout, wcLoginStderr, wcErr := utils.LoginToKubeCluster(fullWorkloadClusterName)
 			if wcLoginStdout != "" {
@@ -89,10 +289,17 @@ Arguments:
 			if wcErr != nil {
 				return fmt.Errorf("failed to log into workload cluster '%s' (short name '%s'): %w", fullWorkloadClusterName, shortWorkloadClusterName, wcErr)
 			}
-			teleportContextToUse = "teleport.giantswarm.io-" + fullWorkloadClusterName
+		}
+
+		// Remember the clusters for the TUI cluster picker; failing to do so is not worth aborting for.
+		if err := utils.RecordClusterUsage(managementCluster, fullWorkloadClusterName); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
 		}
 
 		fmt.Printf("Current Kubernetes context set to: %s\n", teleportContextToUse)
+		if isolatedKubeconfig {
+			fmt.Printf("To use this context in another shell, run: export KUBECONFIG=%s\n", os.Getenv("KUBECONFIG"))
+		}
 		fmt.Println("--------------------------")
 
 		if noTUI {
@@ -196,7 +403,7 @@ Arguments:
 
 			_ = lipgloss.HasDarkBackground()
 
-			initialModel := tui.InitialModel(managementCluster, fullWorkloadClusterName, teleportContextToUse)
+			initialModel := tui.InitialModel(managementCluster, fullWorkloadClusterName, teleportContextToUse, tuiOpts)
 			p := tea.NewProgram(initialModel, tea.WithAltScreen(), tea.WithMouseAllMotion())
 			if _, err := p.Run(); err != nil {
 				fmt.Fprintf(os.Stderr, "Error running TUI: %v\n", err)
@@ -238,6 +445,36 @@ Arguments:
 func newConnectCmd() *cobra.Command {
 	// Add the --no-tui flag
 	connectCmdDef.Flags().BoolVar(&noTUI, "no-tui", false, "Disable TUI and run port forwarding in the background")
+	// Add the leader election flags for shared instances
+	connectCmdDef.Flags().BoolVar(&leaderElection, "leader-election", false, "Only one instance manages port-forwards; others attach read-only")
+	connectCmdDef.Flags().BoolVar(&readOnly, "read-only", false, "Start the TUI without changing anything: no logins, context switches or port-forwards")
+	connectCmdDef.Flags().StringVar(&instanceLockPath, "lock-file", utils.DefaultInstanceLockPath(), "Lock file used for --leader-election")
+	// Add the child process environment flags
+	connectCmdDef.Flags().StringSliceVar(&childEnvAllow, "child-env-allow", nil, "Additional environment variables (NAME or PREFIX*, optionally command:NAME) passed to tsh and kubectl")
+	connectCmdDef.Flags().StringArrayVar(&childEnvSet, "child-env-set", nil, "Environment variable (NAME=VALUE, optionally command:NAME=VALUE) set for tsh and kubectl; repeatable")
+	connectCmdDef.Flags().BoolVar(&childEnvInherit, "child-env-inherit", false, "Pass envctl's complete environment to tsh and kubectl (old behavior, deprecated)")
+	// Add the --force flag
+	connectCmdDef.Flags().BoolVar(&forceSwitch, "force", false, "Switch connections in the TUI without showing the impact preview and asking for confirmation")
+	// Add the --enable-kubectl-pane flag
+	connectCmdDef.Flags().BoolVar(&enableKubectlPane, "enable-kubectl-pane", false, "Allow running kubectl commands from the TUI (':' key)")
+	// Add the alert rule flags
+	connectCmdDef.Flags().IntVar(&alertRules.PortForwardRestarts, "alert-pf-restarts", alertRules.PortForwardRestarts, "Alert when a port-forward restarts more than this many times within --alert-pf-restart-window (0 disables)")
+	connectCmdDef.Flags().DurationVar(&alertRules.PortForwardRestartWindow, "alert-pf-restart-window", alertRules.PortForwardRestartWindow, "Time window for --alert-pf-restarts")
+	connectCmdDef.Flags().DurationVar(&alertRules.ClusterUnhealthyFor, "alert-cluster-unhealthy", alertRules.ClusterUnhealthyFor, "Alert when a cluster stays unhealthy this long (0 disables)")
+	// Add the activity log size flags
+	connectCmdDef.Flags().IntVar(&logBufferLines, "log-buffer-lines", 200, "Maximum number of lines kept in the TUI activity log")
+	connectCmdDef.Flags().IntVar(&logBufferBytes, "log-buffer-bytes", 1<<20, "Maximum total size in bytes of the TUI activity log")
+	connectCmdDef.Flags().StringVar(&lowBandwidth, "low-bandwidth", "auto", "Reduce TUI redraws, borders and colors for slow links: auto (detect SSH), on or off")
+	connectCmdDef.Flags().DurationVar(&healthPauseDuration, "health-pause-duration", 30*time.Minute, "How long 'P' pauses health checks, alerts and automatic reconciliation in the TUI")
+	connectCmdDef.Flags().DurationVar(&maintenanceDuration, "maintenance-duration", time.Hour, "How long a maintenance window ('m', 'M') suppresses automatic reconnects and restarts in the TUI")
+	// Add the --bind-address flag
+	connectCmdDef.Flags().StringArrayVar(&bindAddressEntries, "bind-address", nil, "Local address port-forwards listen on (ADDRESS or NAME=ADDRESS); repeatable, default 127.0.0.1")
+	// Add the --skip-preflight flag
+	connectCmdDef.Flags().BoolVar(&skipPreflight, "skip-preflight", false, "Start even if the preflight checks (free ports, open file limit, disk space) report errors")
+	// Add the kubeconfig isolation flags
+	connectCmdDef.Flags().BoolVar(&isolatedKubeconfig, "isolated-kubeconfig", false, "Write contexts to envctl's own kubeconfig instead of the global one")
+	connectCmdDef.Flags().StringVar(&isolatedKubeconfigPath, "isolated-kubeconfig-path", utils.DefaultIsolatedKubeconfigPath(), "Kubeconfig file used with --isolated-kubeconfig")
+	connectCmdDef.Flags().BoolVar(&temporaryKubeconfig, "temporary-kubeconfig", false, "Use a kubeconfig of this instance only, removed on exit (implies --isolated-kubeconfig)")
 	return connectCmdDef
 }
 
@@ -253,56 +490,27 @@ type portForwardConfig struct {
 }
 
 // getPortForwardConfigs defines the port forwarding configurations.
-// This is similar to what setupPortForwards does in the TUI, but adapted for non-TUI mode.
+// Like setupPortForwards in the TUI, it instantiates utils.DefaultPortForwardTemplates for the clusters' roles.
 func getPortForwardConfigs(mcName, wcName, baseKubeContext string) []portForwardConfig {
 	configs := make([]portForwardConfig, 0)
 
-	mcKubeContext := "teleport.giantswarm.io-" + mcName
-	var wcKubeContext string
+	var mcKubeContext, wcKubeContext string
+	if mcName != "" {
+		mcKubeContext = "teleport.giantswarm.io-" + mcName
+	}
 	if wcName != "" {
 		wcKubeContext = "teleport.giantswarm.io-" + wcName // wcName is already full here e.g. mc-wc
 	}
 
-	// Prometheus for MC
-	if mcName != "" {
-		configs = append(configs, portForwardConfig{
-			label:       "Prometheus (MC)",
-			localPort:   "8080",
-			remotePort:  "8080",
-			kubeContext: mcKubeContext,
-			namespace:   "mimir",
-			service:     "service/mimir-query-frontend",
-		})
-		// Grafana for MC
+	for _, spec := range utils.PortForwardsForConnection(mcKubeContext, wcKubeContext, utils.DefaultPortForwardTemplates) {
 		configs = append(configs, portForwardConfig{
-			label:       "Grafana (MC)",
-			localPort:   "3000",
-			remotePort:  "3000",
-			kubeContext: mcKubeContext,
-			namespace:   "monitoring",
-			service:     "service/grafana",
+			label:       spec.Label,
+			localPort:   spec.LocalPort,
+			remotePort:  spec.RemotePort,
+			kubeContext: spec.Context,
+			namespace:   spec.Namespace,
+			service:     spec.Service,
 		})
 	}
-
-	// Alloy Metrics for WC (if wcName is provided) or MC (if wcName is not provided)
-	alloyLabel := "Alloy Metrics"
-	alloyContext := mcKubeContext // Default to MC context
-
-	if wcName != "" {
-		alloyLabel += " (WC)"
-		alloyContext = wcKubeContext
-	} else {
-		alloyLabel += " (MC)"
-	}
-
-	configs = append(configs, portForwardConfig{
-		label:       alloyLabel,
-		localPort:   "12345",
-		remotePort:  "12345",
-		kubeContext: alloyContext,
-		namespace:   "kube-system",
-		service:     "service/alloy-metrics-cluster",
-	})
-
 	return configs
 }
@@ -33,7 +33,9 @@ func Execute() {
 	// This is used when the --version flag is invoked.
 	rootCmd.SetVersionTemplate(`{{printf "envctl version %s\n" .Version}}`)
 
-	err := rootCmd.Execute()
+	cmd, err := rootCmd.ExecuteC()
+	// Record the run for opt-in usage telemetry; a no-op unless enabled with 'envctl telemetry enable'.
+	recordTelemetry(cmd, err)
 	if err != nil {
 		// Cobra itself usually prints the error. Exiting with a non-zero status code
 		// indicates that an error occurred during execution.
@@ -47,6 +49,7 @@ func init() {
 	rootCmd.AddCommand(newConnectCmd())
 	rootCmd.AddCommand(newVersionCmd())
 	rootCmd.AddCommand(newSelfUpdateCmd())
+	rootCmd.AddCommand(newTelemetryCmd())
 
 	// Example of how to define persistent flags (global for the application):
 	// rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.envctl.yaml)")
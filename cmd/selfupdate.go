@@ -6,11 +6,14 @@ import (
 
 	"github.com/creativeprojects/go-selfupdate"
 	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/envctl/internal/utils"
 )
 
 // githubRepoSlug specifies the GitHub repository (owner/repo) to check for updates.
 const (
 	githubRepoSlug = "giantswarm/envctl" // Replace with your actual repo path
+	githubAPIURL   = "https://api.github.com"
 )
 
 // newSelfUpdateCmd creates the Cobra command for the self-update functionality.
@@ -37,6 +40,9 @@ func runSelfUpdate(cmd *cobra.Command, args []string) error {
 
 	fmt.Printf("Current version: %s\n", currentVersion)
 	fmt.Println("Checking for updates...")
+	if proxy := utils.ProxyForURL(githubAPIURL); proxy != "" {
+		fmt.Printf("Using proxy: %s\n", proxy)
+	}
 
 	updater, err := selfupdate.NewUpdater(selfupdate.Config{
 		// For public GitHub repositories, no specific configuration is usually needed for the updater.
@@ -49,7 +55,7 @@ func runSelfUpdate(cmd *cobra.Command, args []string) error {
 	// DetectLatest fetches the latest release information from the specified GitHub repository.
 	latest, found, err := updater.DetectLatest(context.Background(), selfupdate.ParseSlug(githubRepoSlug))
 	if err != nil {
-		return fmt.Errorf("error detecting latest version: %w", err)
+		return fmt.Errorf("error detecting latest version: %w", utils.AnnotateProxyError(err, githubAPIURL))
 	}
 	if !found {
 		return fmt.Errorf("latest release for %s could not be found", githubRepoSlug)
@@ -74,7 +80,7 @@ func runSelfUpdate(cmd *cobra.Command, args []string) error {
 
 	// Perform the update. This will download the new binary and replace the current one.
 	if err := updater.UpdateTo(context.Background(), latest, exe); err != nil {
-		return fmt.Errorf("update failed: %w", err)
+		return fmt.Errorf("update failed: %w", utils.AnnotateProxyError(err, latest.AssetURL))
 	}
 
 	fmt.Printf("Successfully updated to version %s\n", latest.Version())
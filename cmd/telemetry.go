@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/giantswarm/envctl/internal/utils"
+)
+
+// newTelemetryCmd creates the Cobra command for managing opt-in usage telemetry.
+func newTelemetryCmd() *cobra.Command {
+	telemetryCmd := &cobra.Command{
+		Use:   "telemetry",
+		Short: "Manage anonymized usage telemetry (off unless enabled)",
+		Long: `Telemetry is off by default. When enabled, envctl sends one event per command run to the
+configured endpoint: the command, the names (never values) of the flags used, a coarse error class
+(none, proxy, login, kubernetes, other), envctl version, OS and architecture, the hour of the run
+and a random install ID. Arguments, cluster names, error messages and any other payloads are never sent.
+Events that cannot be sent are kept locally (at most 100) and retried on the next run.`,
+	}
+
+	var endpoint string
+	enableCmd := &cobra.Command{
+		Use:   "enable",
+		Short: "Opt in to telemetry sent to the given endpoint",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			settings, err := utils.EnableTelemetry(endpoint)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Telemetry enabled, sending to %s (install ID %s).\n", settings.Endpoint, settings.InstallID)
+			return nil
+		},
+	}
+	enableCmd.Flags().StringVar(&endpoint, "endpoint", "", "URL telemetry events are POSTed to, e.g. a self-hosted collector")
+	_ = enableCmd.MarkFlagRequired("endpoint")
+
+	disableCmd := &cobra.Command{
+		Use:   "disable",
+		Short: "Opt out of telemetry and discard unsent events",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := utils.DisableTelemetry(); err != nil {
+				return err
+			}
+			fmt.Println("Telemetry disabled.")
+			return nil
+		},
+	}
+
+	previewCmd := &cobra.Command{
+		Use:   "preview",
+		Short: "Show exactly what telemetry would send",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			settings, err := utils.LoadTelemetrySettings()
+			if err != nil {
+				return err
+			}
+			events, err := utils.PendingTelemetryEvents()
+			if err != nil {
+				return err
+			}
+			sample := []utils.TelemetryEvent{utils.NewTelemetryEvent(settings, rootCmd.Version, "envctl connect", []string{"no-tui"}, nil)}
+			switch {
+			case !settings.Enabled:
+				fmt.Println("Telemetry is disabled; nothing is recorded or sent. If enabled, a run of 'envctl connect --no-tui' would send:")
+				events = sample
+			case len(events) == 0:
+				fmt.Printf("Telemetry is enabled, sending to %s. No events are waiting to be sent; a run of 'envctl connect --no-tui' would send:\n", settings.Endpoint)
+				events = sample
+			default:
+				fmt.Printf("Telemetry is enabled, sending to %s. Events waiting to be sent:\n", settings.Endpoint)
+			}
+			data, err := json.MarshalIndent(events, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(data))
+			return nil
+		},
+	}
+
+	telemetryCmd.AddCommand(enableCmd, disableCmd, previewCmd)
+	return telemetryCmd
+}
+
+// recordTelemetry records a finished command run if the user opted in to telemetry.
+// Runs of the telemetry command itself, help, hidden commands and cobra's shell completion
+// (__complete, run on every tab press) are not recorded. Failures never affect the command's outcome.
+func recordTelemetry(cmd *cobra.Command, runErr error) {
+	if cmd == nil || cmd.Name() == "help" || (cmd.Parent() != nil && cmd.Parent().Name() == "telemetry") {
+		return
+	}
+	for c := cmd; c != nil; c = c.Parent() {
+		if c.Hidden || strings.HasPrefix(c.Name(), "__") {
+			return
+		}
+	}
+	settings, err := utils.LoadTelemetrySettings()
+	if err != nil || !settings.Enabled {
+		return
+	}
+	var flags []string
+	cmd.Flags().Visit(func(f *pflag.Flag) { flags = append(flags, f.Name) })
+	_ = utils.RecordTelemetryEvent(settings, utils.NewTelemetryEvent(settings, rootCmd.Version, cmd.CommandPath(), flags, runErr))
+}
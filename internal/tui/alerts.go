@@ -0,0 +1,151 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// alertEvalInterval is how often alert rules are evaluated.
+const alertEvalInterval = 10 * time.Second
+
+// AlertRules configures the built-in alert rules. A zero value disables the respective rule.
+type AlertRules struct {
+	// PortForwardRestarts fires when a port-forward restarted more than this many times within PortForwardRestartWindow.
+	PortForwardRestarts      int
+	PortForwardRestartWindow time.Duration
+	// ClusterUnhealthyFor fires when a cluster's health check has been failing (or not all nodes ready) for this long.
+	ClusterUnhealthyFor time.Duration
+}
+
+// DefaultAlertRules returns the alert rules used unless configured otherwise:
+// more than 3 port-forward restarts in 10 minutes, or a cluster unhealthy for 5 minutes.
+func DefaultAlertRules() AlertRules {
+	return AlertRules{PortForwardRestarts: 3, PortForwardRestartWindow: 10 * time.Minute, ClusterUnhealthyFor: 5 * time.Minute}
+}
+
+// activeAlert is an alert that is currently firing.
+type activeAlert struct {
+	message string    // Human-readable description of the condition.
+	since   time.Time // When the alert started firing.
+}
+
+// alertTickMsg triggers an evaluation of the alert rules.
+type alertTickMsg time.Time
+
+// alertTickCmd schedules the next alert rule evaluation.
+func alertTickCmd() tea.Cmd {
+	return tea.Tick(alertEvalInterval, func(t time.Time) tea.Msg { return alertTickMsg(t) })
+}
+
+// countRestarts returns how many times the port-forward (re)entered the Starting state after having
+// been in another state, within the window ending at now.
+func countRestarts(pf *portForwardProcess, window time.Duration, now time.Time) int {
+	n := 0
+	for _, t := range pf.history {
+		if t.To == pfStateStarting && t.From != "" && now.Sub(t.At) <= window {
+			n++
+		}
+	}
+	return n
+}
+
+// clusterUnhealthy reports whether a cluster's last health check failed or found nodes that are not ready.
+func clusterUnhealthy(health clusterHealthInfo) bool {
+	if health.LastUpdated.IsZero() {
+		return false // Not checked yet
+	}
+	return health.StatusError != nil || health.ReadyNodes < health.TotalNodes
+}
+
+// evaluateAlerts checks all alert rules against the model, starting and resolving alerts.
+// Newly firing and resolved alerts are written to the activity log.
+func evaluateAlerts(m *model, now time.Time) {
+	if m.alerts == nil {
+		m.alerts = make(map[string]activeAlert)
+	}
+	if m.unhealthySince == nil {
+		m.unhealthySince = make(map[string]time.Time)
+	}
+	rules := m.alertRules
+	firing := make(map[string]string)
+
+	if rules.PortForwardRestarts > 0 && rules.PortForwardRestartWindow > 0 {
+		for _, label := range m.portForwardOrder {
+			pf, ok := m.portForwards[label]
+			if !ok {
+				continue
+			}
+			if n := countRestarts(pf, rules.PortForwardRestartWindow, now); n > rules.PortForwardRestarts {
+				firing["pf-restarts/"+label] = fmt.Sprintf("%s restarted %d times in the last %s", label, n, rules.PortForwardRestartWindow)
+			}
+		}
+	}
+
+	if rules.ClusterUnhealthyFor > 0 {
+		clusters := []struct {
+			name   string
+			health clusterHealthInfo
+		}{{m.managementCluster, m.MCHealth}, {m.workloadCluster, m.WCHealth}}
+		for _, c := range clusters {
+			key := "cluster-unhealthy/" + c.name
+			if c.name == "" || !clusterUnhealthy(c.health) {
+				delete(m.unhealthySince, key)
+				continue
+			}
+			since, ok := m.unhealthySince[key]
+			if !ok {
+				since = c.health.LastUpdated
+				m.unhealthySince[key] = since
+			}
+			if d := now.Sub(since); d >= rules.ClusterUnhealthyFor {
+				firing[key] = fmt.Sprintf("cluster %s unhealthy for %s", c.name, d.Round(time.Second))
+			}
+		}
+	}
+
+	// Log transitions in a stable order.
+	keys := make([]string, 0, len(firing)+len(m.alerts))
+	for key := range firing {
+		keys = append(keys, key)
+	}
+	for key := range m.alerts {
+		if _, ok := firing[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		message, isFiring := firing[key]
+		existing, wasFiring := m.alerts[key]
+		switch {
+		case isFiring && !wasFiring:
+			m.alerts[key] = activeAlert{message: message, since: now}
+			m.combinedOutput.Append("[ALERT] " + message)
+		case isFiring:
+			existing.message = message // Keep counts and durations current
+			m.alerts[key] = existing
+		case wasFiring:
+			delete(m.alerts, key)
+			m.combinedOutput.Append("[ALERT RESOLVED] " + existing.message)
+		}
+	}
+}
+
+// sortedAlerts returns the currently firing alerts, oldest first.
+func sortedAlerts(m model) []activeAlert {
+	alerts := make([]activeAlert, 0, len(m.alerts))
+	for _, a := range m.alerts {
+		alerts = append(alerts, a)
+	}
+	sort.Slice(alerts, func(i, j int) bool {
+		if alerts[i].since.Equal(alerts[j].since) {
+			return alerts[i].message < alerts[j].message
+		}
+		return alerts[i].since.Before(alerts[j].since)
+	})
+	return alerts
+}
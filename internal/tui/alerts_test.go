@@ -0,0 +1,45 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEvaluateAlerts(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	pf := &portForwardProcess{label: "Grafana (MC)"}
+	for i := 0; i < 4; i++ {
+		at := now.Add(time.Duration(-8+2*i) * time.Minute)
+		pf.history = append(pf.history,
+			stateTransition{At: at, From: pfStateRunning, To: pfStateStarting},
+			stateTransition{At: at.Add(time.Second), From: pfStateStarting, To: pfStateRunning},
+		)
+	}
+	m := model{
+		managementCluster: "alpha",
+		MCHealth:          clusterHealthInfo{StatusError: errors.New("connection refused"), LastUpdated: now.Add(-6 * time.Minute)},
+		portForwardOrder:  []string{"Grafana (MC)"},
+		portForwards:      map[string]*portForwardProcess{"Grafana (MC)": pf},
+		alertRules:        DefaultAlertRules(),
+		alerts:            make(map[string]activeAlert),
+		unhealthySince:    make(map[string]time.Time),
+		combinedOutput:    newLogBuffer(0, 0),
+	}
+
+	evaluateAlerts(&m, now)
+	if len(m.alerts) != 2 {
+		t.Fatalf("expected 2 firing alerts, got %d: %v", len(m.alerts), m.combinedOutput.Lines())
+	}
+
+	// Once the cluster recovers, its alert resolves and the restart alert keeps firing.
+	m.MCHealth = clusterHealthInfo{ReadyNodes: 3, TotalNodes: 3, LastUpdated: now}
+	evaluateAlerts(&m, now.Add(alertEvalInterval))
+	if _, ok := m.alerts["pf-restarts/Grafana (MC)"]; !ok || len(m.alerts) != 1 {
+		t.Fatalf("expected only the restart alert to remain, got %v", m.alerts)
+	}
+	if last := m.combinedOutput.Last(); !strings.HasPrefix(last, "[ALERT RESOLVED] cluster alpha") {
+		t.Errorf("expected resolution to be logged, got %q", last)
+	}
+}
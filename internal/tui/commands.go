@@ -53,12 +53,13 @@ func getCurrentKubeContextCmd() tea.Cmd {
 
 // performSwitchKubeContextCmd creates a tea.Cmd to attempt switching the active Kubernetes context.
 // - targetContextName: The full name of the Kubernetes context to switch to.
+// - correlationID: ID of the switch operation, echoed back in the result message.
 // Returns a tea.Cmd that, when run, will call utils.SwitchKubeContext and send a kubeContextSwitchedMsg.
-func performSwitchKubeContextCmd(targetContextName string) tea.Cmd {
+func performSwitchKubeContextCmd(targetContextName, correlationID string) tea.Cmd {
 	return func() tea.Msg {
 		// utils.SwitchKubeContext would eventually use client-go
 		err := utils.SwitchKubeContext(targetContextName)
-		return kubeContextSwitchedMsg{TargetContext: targetContextName, err: err}
+		return kubeContextSwitchedMsg{TargetContext: targetContextName, correlationID: correlationID, err: err}
 	}
 }
 
@@ -67,8 +68,9 @@ func performSwitchKubeContextCmd(targetContextName string) tea.Cmd {
 // - clusterName: The name of the cluster to log into (can be MC name or full WC name like "mc-wc").
 // - isMC: True if this login attempt is for a Management Cluster.
 // - desiredWcShortNameToCarry: If isMC is true, this holds the short name of the desired WC to be used in the next step.
+// - correlationID: ID of the connection operation, carried to the next step of the flow.
 // Returns a tea.Cmd that, when run, will call utils.LoginToKubeCluster and send a kubeLoginResultMsg.
-func performKubeLoginCmd(clusterName string, isMC bool, desiredWcShortNameToCarry string) tea.Cmd {
+func performKubeLoginCmd(clusterName string, isMC bool, desiredWcShortNameToCarry, correlationID string) tea.Cmd {
 	return func() tea.Msg {
 		stdout, stderr, err := utils.LoginToKubeCluster(clusterName)
 		return kubeLoginResultMsg{
@@ -77,6 +79,7 @@ func performKubeLoginCmd(clusterName string, isMC bool, desiredWcShortNameToCarr
 			desiredWcShortName: desiredWcShortNameToCarry,
 			loginStdout:        stdout,
 			loginStderr:        stderr,
+			correlationID:      correlationID,
 			err:                err,
 		}
 	}
@@ -88,9 +91,10 @@ func performKubeLoginCmd(clusterName string, isMC bool, desiredWcShortNameToCarr
 // - targetKubeContext: The full Kubernetes context name to switch to (e.g., "teleport.giantswarm.io-mc-wc").
 // - desiredMc: The short name of the Management Cluster for the new connection.
 // - desiredWc: The short name of the Workload Cluster for the new connection (can be empty).
+// - correlationID: ID of the connection operation, carried to the re-initialization step.
 // Returns a tea.Cmd that, when run, attempts the context switch, gets the current context, gathers diagnostics,
 // and then sends a contextSwitchAndReinitializeResultMsg back to the TUI.
-func performPostLoginOperationsCmd(targetKubeContext, desiredMc, desiredWc string) tea.Cmd {
+func performPostLoginOperationsCmd(targetKubeContext, desiredMc, desiredWc, correlationID string) tea.Cmd {
 	return func() tea.Msg {
 		var diagnosticLog strings.Builder
 		diagnosticLog.WriteString(fmt.Sprintf("Attempting to switch context to: %s\n", targetKubeContext))
@@ -104,6 +108,7 @@ func performPostLoginOperationsCmd(targetKubeContext, desiredMc, desiredWc strin
 				desiredMcName: desiredMc,
 				desiredWcName: desiredWc,
 				diagnosticLog: diagnosticLog.String(),
+				correlationID: correlationID,
 			}
 		}
 		diagnosticLog.WriteString("SwitchKubeContext successful.\n")
@@ -118,12 +123,14 @@ func performPostLoginOperationsCmd(targetKubeContext, desiredMc, desiredWc strin
 				desiredMcName:   desiredMc,
 				desiredWcName:   desiredWc,
 				diagnosticLog:   diagnosticLog.String(),
+				correlationID:   correlationID,
 			}
 		}
 		diagnosticLog.WriteString(fmt.Sprintf("GetCurrentKubeContext successful: %s\n", actualCurrentContext))
 
 		// This kubectl call would also ideally use client-go
 		contextsListCmd := exec.Command("kubectl", "config", "get-contexts", "-o", "name")
+		contextsListCmd.Env = utils.ChildEnv("kubectl")
 		contextsListOutput, contextsListErr := contextsListCmd.Output()
 		if contextsListErr != nil {
 			diagnosticLog.WriteString(fmt.Sprintf("kubectl config get-contexts error: %v\nOutput: %s\n", contextsListErr, string(contextsListOutput)))
@@ -136,18 +143,31 @@ func performPostLoginOperationsCmd(targetKubeContext, desiredMc, desiredWc strin
 			desiredMcName:   desiredMc,
 			desiredWcName:   desiredWc,
 			diagnosticLog:   diagnosticLog.String(),
+			correlationID:   correlationID,
 			err:             nil,
 		}
 	}
 }
 
 // fetchClusterListCmd creates a tea.Cmd to asynchronously fetch the list of available management and workload clusters.
-// This is typically used to populate autocompletion suggestions for the new connection input.
+// This is typically used to populate autocompletion suggestions for the new connection input and the cluster picker.
+// Kubeconfig contexts and last-used timestamps are fetched alongside; failures there are not fatal.
 // Returns a tea.Cmd that, when run, will call utils.GetClusterInfo and send a clusterListResultMsg.
 func fetchClusterListCmd() tea.Cmd {
 	return func() tea.Msg {
 		info, err := utils.GetClusterInfo()
-		return clusterListResultMsg{info: info, err: err}
+		kubeContexts, _ := utils.GetKubeContextNames()
+		recent, _ := utils.LoadRecentClusters()
+		return clusterListResultMsg{info: info, kubeContexts: kubeContexts, recent: recent, err: err}
+	}
+}
+
+// recordClusterUsageCmd creates a tea.Cmd that stores the last-used timestamp of the given clusters
+// for the cluster picker. It produces no message; a failure only means the timestamps are not updated.
+func recordClusterUsageCmd(clusterNames ...string) tea.Cmd {
+	return func() tea.Msg {
+		_ = utils.RecordClusterUsage(clusterNames...)
+		return nil
 	}
 }
 
@@ -11,7 +11,7 @@ import (
 // handleSubmitNewConnectionMsg handles the initial request to establish a new connection.
 // It performs the first part of the new connection sequence:
 // 1. Logs the intent to connect.
-// 2. Stops all currently active port-forwarding processes to prepare for the new setup.
+// 2. Stops all currently active port-forwarding processes and cancels pending reconnects to prepare for the new setup.
 // 3. Validates that a management cluster name is provided.
 // 4. If valid, initiates the Kubernetes login process for the Management Cluster by returning a performKubeLoginCmd.
 // - m: The current TUI model.
@@ -19,18 +19,22 @@ import (
 // - existingCmds: A slice of commands that might have been accumulated (though typically not used here as this starts a new flow).
 // Returns the updated model and a command to begin the login sequence or nil if validation fails.
 func handleSubmitNewConnectionMsg(m model, msg submitNewConnectionMsg, existingCmds []tea.Cmd) (model, tea.Cmd) {
-	m.combinedOutput = append(m.combinedOutput, fmt.Sprintf("[SYSTEM] Initiating new connection to MC: %s, WC: %s", msg.mc, msg.wc))
-	m.combinedOutput = append(m.combinedOutput, "[SYSTEM] Step 0: Stopping all existing port-forwarding processes...")
+	tag := correlationTag(msg.correlationID)
+	m.combinedOutput.Append(fmt.Sprintf("[SYSTEM] %sInitiating new connection to MC: %s, WC: %s", tag, msg.mc, msg.wc))
+	m.combinedOutput.Append(fmt.Sprintf("[SYSTEM] %sStep 0: Stopping all existing port-forwarding processes...", tag))
 
 	stoppedCount := 0
 	for pfKey, pf := range m.portForwards {
+		// A pending reconnect would restart the port-forward against the old context during the new login.
+		cancelReconnect(pf)
 		if pf.stopChan != nil {
-			m.combinedOutput = append(m.combinedOutput, fmt.Sprintf("[%s] Sending stop signal...", pf.label))
+			m.combinedOutput.Append(fmt.Sprintf("[%s] %sSending stop signal...", pf.label, tag))
 			close(pf.stopChan)
 			pf.stopChan = nil
 			pf.statusMsg = "Stopped (new conn)"
 			pf.active = false          // Mark as inactive, setupPortForwards will re-evaluate
 			m.portForwards[pfKey] = pf // Ensure changes are written back if pf is a copy
+			recordStateTransition(pf, "new connection")
 			stoppedCount++
 		} else if pf.active { // If it was supposed to be active but had no stopChan (e.g. setup failed before chan was set)
 			pf.statusMsg = "Stopped (new conn)"
@@ -42,22 +46,16 @@ func handleSubmitNewConnectionMsg(m model, msg submitNewConnectionMsg, existingC
 	}
 
 	if stoppedCount > 0 {
-		m.combinedOutput = append(m.combinedOutput, fmt.Sprintf("[SYSTEM] Finished stopping %d port-forwards.", stoppedCount))
+		m.combinedOutput.Append(fmt.Sprintf("[SYSTEM] %sFinished stopping %d port-forwards.", tag, stoppedCount))
 	} else {
-		m.combinedOutput = append(m.combinedOutput, "[SYSTEM] No active port-forwards to stop.")
-	}
-	if len(m.combinedOutput) > maxCombinedOutputLines {
-		m.combinedOutput = m.combinedOutput[len(m.combinedOutput)-maxCombinedOutputLines:]
+		m.combinedOutput.Append(fmt.Sprintf("[SYSTEM] %sNo active port-forwards to stop.", tag))
 	}
 
 	// Proceed with the new connection logic.
 	m.stashedMcName = msg.mc // Used to reconstruct WC name if needed later
 
 	if msg.mc == "" {
-		m.combinedOutput = append(m.combinedOutput, "[SYSTEM ERROR] Management Cluster name cannot be empty.")
-		if len(m.combinedOutput) > maxCombinedOutputLines {
-			m.combinedOutput = m.combinedOutput[len(m.combinedOutput)-maxCombinedOutputLines:]
-		}
+		m.combinedOutput.Append(fmt.Sprintf("[SYSTEM ERROR] %sManagement Cluster name cannot be empty.", tag))
 		// Reset input mode
 		m.isConnectingNew = false
 		m.newConnectionInput.Blur()
@@ -69,13 +67,10 @@ func handleSubmitNewConnectionMsg(m model, msg submitNewConnectionMsg, existingC
 		return m, nil // No command, user needs to try 'n' again or quit.
 	}
 
-	m.combinedOutput = append(m.combinedOutput, fmt.Sprintf("[SYSTEM] Step 1: Logging into Management Cluster: %s...", msg.mc))
-	if len(m.combinedOutput) > maxCombinedOutputLines {
-		m.combinedOutput = m.combinedOutput[len(m.combinedOutput)-maxCombinedOutputLines:]
-	}
+	m.combinedOutput.Append(fmt.Sprintf("[SYSTEM] %sStep 1: Logging into Management Cluster: %s...", tag, msg.mc))
 	// Return a new command to start the login process.
 	// We are not batching with existingCmds here as this handler starts a new logical flow.
-	return m, performKubeLoginCmd(msg.mc, true, msg.wc)
+	return m, performKubeLoginCmd(msg.mc, true, msg.wc, msg.correlationID)
 }
 
 // handleKubeLoginResultMsg processes the outcome of a `tsh kube login` attempt (performKubeLoginCmd).
@@ -91,23 +86,25 @@ func handleSubmitNewConnectionMsg(m model, msg submitNewConnectionMsg, existingC
 // - cmds: A slice of commands that might have been accumulated.
 // Returns the updated model and a command for the next step in the connection flow or nil if login failed or no next step is taken from here.
 func handleKubeLoginResultMsg(m model, msg kubeLoginResultMsg, cmds []tea.Cmd) (model, tea.Cmd) {
+	tag := correlationTag(msg.correlationID)
+
 	// Append login output to the combined log first, regardless of error
 	if strings.TrimSpace(msg.loginStdout) != "" {
-		m.combinedOutput = append(m.combinedOutput, strings.Split(strings.TrimRight(msg.loginStdout, "\n"), "\n")...)
+		m.combinedOutput.Append(strings.Split(strings.TrimRight(msg.loginStdout, "\n"), "\n")...)
 	}
 	if strings.TrimSpace(msg.loginStderr) != "" {
 		for _, line := range strings.Split(strings.TrimRight(msg.loginStderr, "\n"), "\n") {
-			m.combinedOutput = append(m.combinedOutput, "[tsh stderr] "+line)
+			m.combinedOutput.Append("[tsh stderr] " + line)
 		}
 	}
 
 	if msg.err != nil {
-		m.combinedOutput = append(m.combinedOutput, fmt.Sprintf("[SYSTEM ERROR] Login failed for %s: %v", msg.clusterName, msg.err))
+		m.combinedOutput.Append(fmt.Sprintf("[SYSTEM ERROR] %sLogin failed for %s: %v", tag, msg.clusterName, msg.err))
 		// Potentially reset isConnectingNew = false here or offer retry to user?
 		// For now, just log and return.
 		return m, nil
 	}
-	m.combinedOutput = append(m.combinedOutput, fmt.Sprintf("[SYSTEM] Login successful for: %s", msg.clusterName))
+	m.combinedOutput.Append(fmt.Sprintf("[SYSTEM] %sLogin successful for: %s", tag, msg.clusterName))
 
 	var nextCmds []tea.Cmd
 	if msg.isMC {
@@ -124,14 +121,14 @@ func handleKubeLoginResultMsg(m model, msg kubeLoginResultMsg, cmds []tea.Cmd) (
 			} else {
 				wcIdentifierForLogin = desiredMcForNextStep + "-" + desiredWcForNextStep
 			}
-			m.combinedOutput = append(m.combinedOutput, fmt.Sprintf("[SYSTEM] Step 2: Logging into Workload Cluster: %s...", wcIdentifierForLogin))
-			nextCmds = append(nextCmds, performKubeLoginCmd(wcIdentifierForLogin, false, "")) // For WC login, desiredWcShortNameToCarry is ""
+			m.combinedOutput.Append(fmt.Sprintf("[SYSTEM] %sStep 2: Logging into Workload Cluster: %s...", tag, wcIdentifierForLogin))
+			nextCmds = append(nextCmds, performKubeLoginCmd(wcIdentifierForLogin, false, "", msg.correlationID)) // For WC login, desiredWcShortNameToCarry is ""
 		} else {
 			// No WC specified, proceed to context switch and re-initialize for MC only.
-			m.combinedOutput = append(m.combinedOutput, "[SYSTEM] Step 2: No Workload Cluster specified. Proceeding to context switch for MC.")
+			m.combinedOutput.Append(fmt.Sprintf("[SYSTEM] %sStep 2: No Workload Cluster specified. Proceeding to context switch for MC.", tag))
 			// desiredMcForNextStep is the MC identifier (e.g., "myinstallation")
 			targetKubeContext := "teleport.giantswarm.io-" + desiredMcForNextStep
-			nextCmds = append(nextCmds, performPostLoginOperationsCmd(targetKubeContext, desiredMcForNextStep, ""))
+			nextCmds = append(nextCmds, performPostLoginOperationsCmd(targetKubeContext, desiredMcForNextStep, "", msg.correlationID))
 		}
 	} else {
 		// WC Login was successful. msg.clusterName here is the WC identifier (e.g., "myinstallation-mycluster") used for login.
@@ -152,11 +149,11 @@ func handleKubeLoginResultMsg(m model, msg kubeLoginResultMsg, cmds []tea.Cmd) (
 			shortWcName = msg.clusterName // This might be problematic if msg.clusterName is complex and not just short WC
 		}
 
-		m.combinedOutput = append(m.combinedOutput, "[SYSTEM] Step 3: Workload Cluster login successful. Proceeding to context switch for WC.")
+		m.combinedOutput.Append(fmt.Sprintf("[SYSTEM] %sStep 3: Workload Cluster login successful. Proceeding to context switch for WC.", tag))
 		// msg.clusterName is the WC identifier (e.g., "myinstallation-mycluster") that was successfully logged into.
 		// This is the correct identifier to form the targetKubeContext.
 		targetKubeContext := "teleport.giantswarm.io-" + msg.clusterName
-		nextCmds = append(nextCmds, performPostLoginOperationsCmd(targetKubeContext, finalMcName, shortWcName))
+		nextCmds = append(nextCmds, performPostLoginOperationsCmd(targetKubeContext, finalMcName, shortWcName, msg.correlationID))
 	}
 	return m, tea.Batch(append(cmds, nextCmds...)...)
 }
@@ -181,18 +178,19 @@ func handleKubeLoginResultMsg(m model, msg kubeLoginResultMsg, cmds []tea.Cmd) (
 // - existingCmds: A slice of commands that might have been accumulated.
 // Returns the updated model and a batch of commands to re-initialize the TUI or nil if an error occurred.
 func handleContextSwitchAndReinitializeResultMsg(m model, msg contextSwitchAndReinitializeResultMsg, existingCmds []tea.Cmd) (model, tea.Cmd) {
+	tag := correlationTag(msg.correlationID)
 	if msg.diagnosticLog != "" {
-		m.combinedOutput = append(m.combinedOutput, "--- Diagnostic Log (Context Switch Phase) ---")
-		m.combinedOutput = append(m.combinedOutput, strings.Split(strings.TrimSpace(msg.diagnosticLog), "\n")...)
-		m.combinedOutput = append(m.combinedOutput, "--- End Diagnostic Log ---")
+		m.combinedOutput.Append(fmt.Sprintf("--- %sDiagnostic Log (Context Switch Phase) ---", tag))
+		m.combinedOutput.Append(strings.Split(strings.TrimSpace(msg.diagnosticLog), "\n")...)
+		m.combinedOutput.Append("--- End Diagnostic Log ---")
 	}
 	if msg.err != nil {
-		m.combinedOutput = append(m.combinedOutput, fmt.Sprintf("[SYSTEM ERROR] Context switch/re-init failed: %v", msg.err))
+		m.combinedOutput.Append(fmt.Sprintf("[SYSTEM ERROR] %sContext switch/re-init failed: %v", tag, msg.err))
 		// Consider how to provide feedback or allow user to retry/cancel
 		return m, nil
 	}
 
-	m.combinedOutput = append(m.combinedOutput, fmt.Sprintf("[SYSTEM] Successfully switched context to: %s. Re-initializing TUI.", msg.switchedContext))
+	m.combinedOutput.Append(fmt.Sprintf("[SYSTEM] %sSuccessfully switched context to: %s. Re-initializing TUI.", tag, msg.switchedContext))
 
 	// Apply new cluster names to the model
 	m.managementCluster = msg.desiredMcName
@@ -208,7 +206,12 @@ func handleContextSwitchAndReinitializeResultMsg(m model, msg contextSwitchAndRe
 	}
 
 	// Reset and set up new port forwards
+	previous := m.portForwards
 	setupPortForwards(&m, m.managementCluster, m.workloadCluster) // This clears and rebuilds portForwards map and order
+	keepStateHistory(previous, m.portForwards)
+
+	// The restarted port-forwards are spawned by this connection operation.
+	assignPortForwardCorrelationIDs(&m, msg.correlationID)
 
 	// Reset focus
 	if len(m.portForwardOrder) > 0 {
@@ -236,6 +239,9 @@ func handleContextSwitchAndReinitializeResultMsg(m model, msg contextSwitchAndRe
 		}
 	}
 
+	// Remember the clusters for the picker's last-used column
+	newInitCmds = append(newInitCmds, recordClusterUsageCmd(m.managementCluster, m.getWorkloadClusterContextIdentifier()))
+
 	// Start port-forwarding processes for the new setup using the centralized function
 	initialPfCmds := getInitialPortForwardCmds(&m)
 	newInitCmds = append(newInitCmds, initialPfCmds...)
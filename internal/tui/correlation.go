@@ -0,0 +1,51 @@
+package tui
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+)
+
+// newCorrelationID generates a short random identifier for a user-initiated operation
+// (e.g., a new connection, a port-forward restart or a context switch).
+// All log lines produced by the operation, and by any operations it spawns, carry this ID
+// so they can be correlated in the activity log.
+func newCorrelationID() string {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		// crypto/rand should not fail; an empty ID simply disables tagging for this operation.
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
+
+// childCorrelationID derives a hierarchical ID for the n-th operation spawned by the parent operation.
+// For example, the second port-forward started by connection "1a2b3c4d" gets "1a2b3c4d.2".
+// If parent is empty, a fresh root ID is returned instead.
+func childCorrelationID(parent string, n int) string {
+	if parent == "" {
+		return newCorrelationID()
+	}
+	return fmt.Sprintf("%s.%d", parent, n)
+}
+
+// correlationTag formats a correlation ID for inclusion in a log line (e.g., "[op:1a2b3c4d] ").
+// It returns an empty string if no ID is set, so it can be prepended unconditionally.
+func correlationTag(id string) string {
+	if id == "" {
+		return ""
+	}
+	return fmt.Sprintf("[op:%s] ", id)
+}
+
+// assignPortForwardCorrelationIDs gives every configured port-forward a child ID of the given parent operation,
+// in display order. It is used when a batch of port-forwards is (re)started by a single operation.
+func assignPortForwardCorrelationIDs(m *model, parent string) {
+	n := 0
+	for _, label := range m.portForwardOrder {
+		if pf, ok := m.portForwards[label]; ok {
+			n++
+			pf.correlationID = childCorrelationID(parent, n)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// pfStateDegraded is a running port-forward that is not fully healthy, see portForwardHealth.
+	pfStateDegraded = "Degraded"
+	// flapRestarts is the number of restarts within flapWindow after which a running port-forward is considered flapping.
+	flapRestarts = 2
+	// flapWindow is the time window used to detect flapping port-forwards.
+	flapWindow = 10 * time.Minute
+	// degradedHealthWeight is how much a degraded service counts towards the environment health rollup.
+	degradedHealthWeight = 0.5
+)
+
+// portForwardHealth refines portForwardState with dependency health: a running port-forward is
+// Degraded if it keeps restarting or if the cluster it depends on is unhealthy or missing ready nodes.
+// - m: The current TUI model, used to look up the cluster the port-forward depends on.
+// - pf: The port-forward to assess.
+// Returns the state and, for Degraded, the reason.
+func portForwardHealth(m model, pf *portForwardProcess) (state, reason string) {
+	state = portForwardState(pf)
+	if state != pfStateRunning {
+		return state, ""
+	}
+	if n := countRestarts(pf, flapWindow, time.Now()); n >= flapRestarts {
+		return pfStateDegraded, fmt.Sprintf("restarted %d times in the last %s", n, flapWindow)
+	}
+	clusterName, health := m.clusterForPortForward(pf)
+	switch {
+	case health.IsLoading || health.LastUpdated.IsZero():
+		return state, ""
+	case health.StatusError != nil:
+		return pfStateDegraded, fmt.Sprintf("cluster %s failed its health check", clusterName)
+	case health.ReadyNodes < health.TotalNodes:
+		return pfStateDegraded, fmt.Sprintf("cluster %s has only %d/%d nodes ready", clusterName, health.ReadyNodes, health.TotalNodes)
+	}
+	return state, ""
+}
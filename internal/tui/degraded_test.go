@@ -0,0 +1,55 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPortForwardHealth(t *testing.T) {
+	now := time.Now()
+	running := func() *portForwardProcess {
+		return &portForwardProcess{label: "Grafana (MC)", active: true, forwardingEstablished: true}
+	}
+	healthy := clusterHealthInfo{ReadyNodes: 3, TotalNodes: 3, LastUpdated: now}
+
+	tests := []struct {
+		name       string
+		pf         *portForwardProcess
+		mcHealth   clusterHealthInfo
+		wantState  string
+		wantReason string
+	}{
+		{name: "healthy", pf: running(), mcHealth: healthy, wantState: pfStateRunning},
+		{name: "health not checked yet", pf: running(), mcHealth: clusterHealthInfo{}, wantState: pfStateRunning},
+		{name: "stopped is not degraded", pf: &portForwardProcess{}, mcHealth: clusterHealthInfo{StatusError: errors.New("x"), LastUpdated: now}, wantState: pfStateStopped},
+		{name: "cluster failing", pf: running(), mcHealth: clusterHealthInfo{StatusError: errors.New("x"), LastUpdated: now}, wantState: pfStateDegraded, wantReason: "failed its health check"},
+		{name: "nodes not ready", pf: running(), mcHealth: clusterHealthInfo{ReadyNodes: 2, TotalNodes: 3, LastUpdated: now}, wantState: pfStateDegraded, wantReason: "2/3 nodes ready"},
+		{name: "flapping", pf: func() *portForwardProcess {
+			pf := running()
+			for i := 0; i < flapRestarts; i++ {
+				pf.history = append(pf.history, stateTransition{At: now.Add(-time.Minute), From: pfStateRunning, To: pfStateStarting})
+			}
+			return pf
+		}(), mcHealth: healthy, wantState: pfStateDegraded, wantReason: "restarted 2 times"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := model{managementCluster: "alpha", MCHealth: tt.mcHealth}
+			state, reason := portForwardHealth(m, tt.pf)
+			if state != tt.wantState || !strings.Contains(reason, tt.wantReason) {
+				t.Errorf("got (%q, %q), want (%q, containing %q)", state, reason, tt.wantState, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestHealthRollupWeightsDegraded(t *testing.T) {
+	now := time.Now()
+	samples := []healthSample{{At: now, Healthy: 1, Degraded: 2, Total: 4}}
+	pct, ok := healthRollup(samples, now.Add(-time.Minute), now.Add(time.Minute))
+	if !ok || pct != 50 {
+		t.Fatalf("got %v%% (ok=%v), want 50%%", pct, ok)
+	}
+}
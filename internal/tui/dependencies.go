@@ -0,0 +1,173 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Kinds of nodes in the dependency graph of a connection.
+const (
+	nodeCluster     = "cluster"
+	nodePortForward = "port-forward"
+)
+
+// Cluster states used in the dependency graph, next to the port-forward states of portForwardHealth.
+const (
+	clusterStateChecking = "Checking"
+	clusterStateHealthy  = "Healthy"
+)
+
+// dependencyNode is a cluster or port-forward of the connection together with its current state.
+type dependencyNode struct {
+	Key   string // Panel key: mcPaneFocusKey, wcPaneFocusKey or the port-forward label.
+	Kind  string // nodeCluster or nodePortForward.
+	Name  string // Cluster name or port-forward label.
+	State string
+}
+
+// String formats the node for display, e.g. "cluster alpha (healthy)".
+func (n dependencyNode) String() string {
+	if n.Kind == nodeCluster {
+		return fmt.Sprintf("cluster %s (%s)", n.Name, strings.ToLower(n.State))
+	}
+	return fmt.Sprintf("%s (%s)", n.Name, strings.ToLower(n.State))
+}
+
+// dependencyClosure is everything a node transitively depends on and everything that transitively depends on it.
+type dependencyClosure struct {
+	Node       dependencyNode
+	Upstream   []dependencyNode // Must be healthy for Node to work.
+	Downstream []dependencyNode // Stops working if Node fails.
+}
+
+// dependencyGraphKeys returns the panel keys of all nodes of the connection in display order.
+func dependencyGraphKeys(m model) []string {
+	var keys []string
+	if m.managementCluster != "" {
+		keys = append(keys, mcPaneFocusKey)
+	}
+	if m.workloadCluster != "" {
+		keys = append(keys, wcPaneFocusKey)
+	}
+	for _, label := range m.portForwardOrder {
+		if _, ok := m.portForwards[label]; ok {
+			keys = append(keys, label)
+		}
+	}
+	return keys
+}
+
+// directDependencies returns the panel keys of the nodes the given node depends on directly:
+// a port-forward depends on the cluster it targets; clusters depend on nothing envctl manages.
+func directDependencies(m model, key string) []string {
+	if pf, ok := m.portForwards[key]; ok {
+		if pf.isWC {
+			return []string{wcPaneFocusKey}
+		}
+		return []string{mcPaneFocusKey}
+	}
+	return nil
+}
+
+// dependencyNodeFor describes the node with the given panel key.
+// Returns false if the connection has no such cluster or port-forward.
+func dependencyNodeFor(m model, key string) (dependencyNode, bool) {
+	switch key {
+	case mcPaneFocusKey:
+		if m.managementCluster == "" {
+			return dependencyNode{}, false
+		}
+		return dependencyNode{Key: key, Kind: nodeCluster, Name: m.managementCluster, State: clusterState(m.MCHealth)}, true
+	case wcPaneFocusKey:
+		if m.workloadCluster == "" {
+			return dependencyNode{}, false
+		}
+		return dependencyNode{Key: key, Kind: nodeCluster, Name: m.workloadCluster, State: clusterState(m.WCHealth)}, true
+	}
+	pf, ok := m.portForwards[key]
+	if !ok {
+		return dependencyNode{}, false
+	}
+	state, _ := portForwardHealth(m, pf)
+	return dependencyNode{Key: key, Kind: nodePortForward, Name: pf.label, State: state}, true
+}
+
+// clusterState summarises a cluster's health for the dependency graph.
+func clusterState(health clusterHealthInfo) string {
+	switch {
+	case health.IsLoading || health.LastUpdated.IsZero():
+		return clusterStateChecking
+	case health.StatusError != nil:
+		return pfStateFailed
+	case health.ReadyNodes < health.TotalNodes:
+		return pfStateDegraded
+	default:
+		return clusterStateHealthy
+	}
+}
+
+// dependencyClosureOf computes the upstream and downstream closure of a cluster or port-forward,
+// with the current state of every node. Nodes are listed in display order.
+// - key: The panel key of the node, i.e. mcPaneFocusKey, wcPaneFocusKey or a port-forward label.
+// Returns false if the connection has no such node.
+func dependencyClosureOf(m model, key string) (dependencyClosure, bool) {
+	node, ok := dependencyNodeFor(m, key)
+	if !ok {
+		return dependencyClosure{}, false
+	}
+	keys := dependencyGraphKeys(m)
+
+	upstream := map[string]bool{}
+	queue := []string{key}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, dep := range directDependencies(m, current) {
+			if !upstream[dep] && dep != key {
+				upstream[dep] = true
+				queue = append(queue, dep)
+			}
+		}
+	}
+
+	downstream := map[string]bool{key: true}
+	for changed := true; changed; {
+		changed = false
+		for _, k := range keys {
+			if downstream[k] {
+				continue
+			}
+			for _, dep := range directDependencies(m, k) {
+				if downstream[dep] {
+					downstream[k], changed = true, true
+					break
+				}
+			}
+		}
+	}
+	delete(downstream, key)
+
+	closure := dependencyClosure{Node: node}
+	for _, k := range keys {
+		n, ok := dependencyNodeFor(m, k)
+		if !ok {
+			continue
+		}
+		if upstream[k] {
+			closure.Upstream = append(closure.Upstream, n)
+		}
+		if downstream[k] {
+			closure.Downstream = append(closure.Downstream, n)
+		}
+	}
+	return closure, true
+}
+
+// joinDependencyNodes formats nodes as a comma-separated list.
+func joinDependencyNodes(nodes []dependencyNode) string {
+	names := make([]string, len(nodes))
+	for i, n := range nodes {
+		names[i] = n.String()
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,51 @@
+package tui
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDependencyClosureOf(t *testing.T) {
+	now := time.Now()
+	m := model{
+		managementCluster: "alpha",
+		workloadCluster:   "alpha-wc",
+		MCHealth:          clusterHealthInfo{ReadyNodes: 3, TotalNodes: 3, LastUpdated: now},
+		WCHealth:          clusterHealthInfo{StatusError: errors.New("x"), LastUpdated: now},
+		portForwardOrder:  []string{mcPaneFocusKey, wcPaneFocusKey, "Grafana (MC)", "Alloy Metrics (WC)", "Prometheus (MC)"},
+		portForwards: map[string]*portForwardProcess{
+			"Grafana (MC)":       {label: "Grafana (MC)", active: true, forwardingEstablished: true},
+			"Alloy Metrics (WC)": {label: "Alloy Metrics (WC)", isWC: true, active: true, forwardingEstablished: true},
+			"Prometheus (MC)":    {label: "Prometheus (MC)"},
+		},
+	}
+	names := func(nodes []dependencyNode) []string {
+		var out []string
+		for _, n := range nodes {
+			out = append(out, n.String())
+		}
+		return out
+	}
+
+	closure, ok := dependencyClosureOf(m, mcPaneFocusKey)
+	if !ok || closure.Node.State != clusterStateHealthy {
+		t.Fatalf("MC: got %+v, %v", closure, ok)
+	}
+	if want := []string{"Grafana (MC) (running)", "Prometheus (MC) (stopped)"}; !reflect.DeepEqual(names(closure.Downstream), want) || len(closure.Upstream) != 0 {
+		t.Errorf("MC downstream: got %v, want %v", names(closure.Downstream), want)
+	}
+
+	closure, _ = dependencyClosureOf(m, "Alloy Metrics (WC)")
+	if want := []string{"cluster alpha-wc (failed)"}; !reflect.DeepEqual(names(closure.Upstream), want) || len(closure.Downstream) != 0 {
+		t.Errorf("Alloy: got upstream %v, downstream %v", names(closure.Upstream), names(closure.Downstream))
+	}
+	if closure.Node.State != pfStateDegraded {
+		t.Errorf("Alloy: got state %s, want degraded by its failing cluster", closure.Node.State)
+	}
+
+	if _, ok := dependencyClosureOf(m, "unknown"); ok {
+		t.Error("expected no closure for an unknown key")
+	}
+}
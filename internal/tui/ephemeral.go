@@ -0,0 +1,217 @@
+package tui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/giantswarm/envctl/internal/utils"
+)
+
+// ephemeralForm holds the state of the input used to create an ephemeral port-forward ('f').
+type ephemeralForm struct {
+	visible bool
+	input   textinput.Model
+	err     string // Validation error of the last submitted input, shown below the input.
+}
+
+// ephemeralSpec is a parsed ephemeral port-forward request.
+type ephemeralSpec struct {
+	namespace string
+	resource  string        // e.g. "service/grafana" or "pod/debug-0".
+	port      string        // "local:remote".
+	ttl       time.Duration // 0 keeps the port-forward until the connection changes or envctl exits.
+}
+
+// ephemeralExpiredMsg is delivered when the TTL of an ephemeral port-forward is over.
+type ephemeralExpiredMsg struct {
+	label     string
+	expiresAt time.Time // Distinguishes the port-forward from a later one with the same label.
+}
+
+// newEphemeralForm creates a hidden ephemeral port-forward form.
+func newEphemeralForm() ephemeralForm {
+	ti := textinput.New()
+	ti.Prompt = "forward "
+	ti.Placeholder = "monitoring/grafana 3001:3000 30m"
+	ti.CharLimit = 256
+	return ephemeralForm{input: ti}
+}
+
+// ephemeralKinds are the target kinds accepted by parseEphemeralSpec, see utils.StartPortForwardClientGo.
+var ephemeralKinds = map[string]bool{"service": true, "deployment": true, "pod": true}
+
+// parseEphemeralSpec parses "<namespace>/[<kind>/]<name> <local>[:<remote>] [<ttl>]".
+// The kind is service, deployment or pod and defaults to "service"; for pods the name may be
+// a label selector such as "app=debug". The remote port defaults to the local port.
+func parseEphemeralSpec(input string) (ephemeralSpec, error) {
+	fields := strings.Fields(input)
+	if len(fields) < 2 || len(fields) > 3 {
+		return ephemeralSpec{}, fmt.Errorf("expected '<namespace>/[<kind>/]<name> <local>[:<remote>] [<ttl>]'")
+	}
+
+	var spec ephemeralSpec
+	parts := strings.SplitN(fields[0], "/", 3) // Label selectors may contain '/', e.g. app.kubernetes.io/name=x.
+	switch {
+	case len(parts) == 2 && parts[0] != "" && parts[1] != "":
+		spec.namespace, spec.resource = parts[0], "service/"+parts[1]
+	case len(parts) == 3 && parts[0] != "" && parts[1] != "" && parts[2] != "":
+		if !ephemeralKinds[parts[1]] {
+			return ephemeralSpec{}, fmt.Errorf("unsupported kind %q, expected service, deployment or pod", parts[1])
+		}
+		spec.namespace, spec.resource = parts[0], parts[1]+"/"+parts[2]
+	default:
+		return ephemeralSpec{}, fmt.Errorf("invalid target %q, expected <namespace>/[<kind>/]<name>", fields[0])
+	}
+
+	ports := strings.Split(fields[1], ":")
+	if len(ports) == 1 {
+		ports = append(ports, ports[0])
+	}
+	if len(ports) != 2 {
+		return ephemeralSpec{}, fmt.Errorf("invalid ports %q, expected <local>[:<remote>]", fields[1])
+	}
+	for _, p := range ports {
+		if n, err := strconv.Atoi(p); err != nil || n < 1 || n > 65535 {
+			return ephemeralSpec{}, fmt.Errorf("invalid port %q", p)
+		}
+	}
+	spec.port = ports[0] + ":" + ports[1]
+
+	if len(fields) == 3 {
+		ttl, err := time.ParseDuration(fields[2])
+		if err != nil || ttl <= 0 {
+			return ephemeralSpec{}, fmt.Errorf("invalid TTL %q, expected a duration like 30m", fields[2])
+		}
+		spec.ttl = ttl
+	}
+	return spec, nil
+}
+
+// createEphemeralPortForward adds a port-forward that is not part of the standard set for the connection
+// and starts it against the focused cluster (see kubectlContextForFocus). Ephemeral port-forwards are
+// dropped when the connection changes and, if a TTL is given, when it expires.
+// Returns the updated model and the commands starting the port-forward and its expiry timer,
+// or an error if the request conflicts with an existing port-forward.
+func createEphemeralPortForward(m model, spec ephemeralSpec) (model, tea.Cmd, error) {
+	localPort := strings.Split(spec.port, ":")[0]
+	for _, pf := range m.portForwards {
+		if strings.Split(pf.port, ":")[0] == localPort {
+			return m, nil, fmt.Errorf("local port %s is already used by %s", localPort, pf.label)
+		}
+	}
+	kubeContext := kubectlContextForFocus(m)
+	isWC := m.workloadCluster != "" && kubeContext == utils.TeleportContextName(m.getWorkloadClusterContextIdentifier())
+	cluster := "MC"
+	if isWC {
+		cluster = "WC"
+	}
+	label := fmt.Sprintf("Ephemeral %s/%s (%s)", spec.namespace, strings.TrimPrefix(spec.resource, "service/"), cluster)
+	if _, exists := m.portForwards[label]; exists {
+		return m, nil, fmt.Errorf("%s already exists", label)
+	}
+	if m.TUIChannel == nil {
+		return m, nil, fmt.Errorf("TUIChannel is nil")
+	}
+
+	pf := &portForwardProcess{
+		label:         label,
+		port:          spec.port,
+		isWC:          isWC,
+		context:       kubeContext,
+		namespace:     spec.namespace,
+		service:       spec.resource,
+		active:        true,
+		statusMsg:     "Awaiting Setup...",
+		correlationID: newCorrelationID(),
+		ephemeral:     true,
+	}
+	cmds := []tea.Cmd{startPortForwardCmd(pf.label, pf.context, pf.namespace, pf.service, pf.port, m.TUIChannel)}
+	expiry := "until the connection changes"
+	if spec.ttl > 0 {
+		pf.expiresAt = time.Now().Add(spec.ttl)
+		expiry = "until " + pf.expiresAt.Format("15:04:05")
+		expiredMsg := ephemeralExpiredMsg{label: label, expiresAt: pf.expiresAt}
+		cmds = append(cmds, tea.Tick(spec.ttl, func(time.Time) tea.Msg { return expiredMsg }))
+	}
+	recordStateTransition(pf, "ephemeral port-forward created")
+
+	m.portForwards[label] = pf
+	m.portForwardOrder = append(m.portForwardOrder, label)
+	m.focusedPanelKey = label
+	m.combinedOutput.Append(fmt.Sprintf("[SYSTEM] %sCreated ephemeral port-forward %s: %s %s via %s, %s.",
+		correlationTag(pf.correlationID), label, spec.port, spec.resource, kubeContext, expiry))
+	return m, tea.Batch(cmds...), nil
+}
+
+// removeEphemeralPortForward stops an ephemeral port-forward and removes its panel.
+// - reason: Why it is removed, for the activity log (e.g. "TTL expired").
+func removeEphemeralPortForward(m model, label, reason string) model {
+	pf, ok := m.portForwards[label]
+	if !ok || !pf.ephemeral {
+		return m
+	}
+	if pf.stopChan != nil {
+		close(pf.stopChan)
+		pf.stopChan = nil
+	}
+	delete(m.portForwards, label)
+	for i, key := range m.portForwardOrder {
+		if key == label {
+			m.portForwardOrder = append(m.portForwardOrder[:i:i], m.portForwardOrder[i+1:]...)
+			if m.focusedPanelKey == label && i > 0 {
+				m.focusedPanelKey = m.portForwardOrder[i-1]
+			}
+			break
+		}
+	}
+	m.combinedOutput.Append(fmt.Sprintf("[SYSTEM] %sRemoved ephemeral port-forward %s: %s.", correlationTag(pf.correlationID), label, reason))
+	return m
+}
+
+// handleEphemeralExpiredMsg removes an ephemeral port-forward whose TTL is over,
+// unless it was already removed or replaced.
+func handleEphemeralExpiredMsg(m model, msg ephemeralExpiredMsg) model {
+	if pf, ok := m.portForwards[msg.label]; ok && pf.ephemeral && pf.expiresAt.Equal(msg.expiresAt) {
+		m = removeEphemeralPortForward(m, msg.label, "TTL expired")
+	}
+	return m
+}
+
+// handleKeyMsgEphemeralForm processes key presses while the ephemeral port-forward form is open.
+// - Enter: Validates the input and creates the port-forward.
+// - Esc: Closes the form.
+// Other keys edit the input.
+func handleKeyMsgEphemeralForm(m model, keyMsg tea.KeyMsg) (model, tea.Cmd) {
+	switch keyMsg.Type {
+	case tea.KeyEsc:
+		m.ephemeral.visible = false
+		m.ephemeral.err = ""
+		m.ephemeral.input.Blur()
+		return m, nil
+	case tea.KeyEnter:
+		spec, err := parseEphemeralSpec(m.ephemeral.input.Value())
+		if err != nil {
+			m.ephemeral.err = err.Error()
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m, cmd, err = createEphemeralPortForward(m, spec)
+		if err != nil {
+			m.ephemeral.err = err.Error()
+			return m, nil
+		}
+		m.ephemeral.visible = false
+		m.ephemeral.err = ""
+		m.ephemeral.input.Reset()
+		m.ephemeral.input.Blur()
+		return m, cmd
+	}
+	var cmd tea.Cmd
+	m.ephemeral.input, cmd = m.ephemeral.input.Update(keyMsg)
+	return m, cmd
+}
@@ -0,0 +1,97 @@
+package tui
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestParseEphemeralSpec(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    ephemeralSpec
+		wantErr bool
+	}{
+		{input: "monitoring/grafana 3001:3000 30m", want: ephemeralSpec{namespace: "monitoring", resource: "service/grafana", port: "3001:3000", ttl: 30 * time.Minute}},
+		{input: "kube-system/pod/coredns-0 9153", want: ephemeralSpec{namespace: "kube-system", resource: "pod/coredns-0", port: "9153:9153"}},
+		{input: "monitoring/deployment/grafana 3000", want: ephemeralSpec{namespace: "monitoring", resource: "deployment/grafana", port: "3000:3000"}},
+		{input: "debug/pod/app.kubernetes.io/name=toolbox 8080", want: ephemeralSpec{namespace: "debug", resource: "pod/app.kubernetes.io/name=toolbox", port: "8080:8080"}},
+		{input: "monitoring/statefulset/grafana 3000", wantErr: true},
+		{input: "grafana 3000", wantErr: true},
+		{input: "monitoring/grafana 70000", wantErr: true},
+		{input: "monitoring/grafana 3000 soon", wantErr: true},
+		{input: "monitoring/grafana", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := parseEphemeralSpec(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Fatalf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEphemeralPortForwardLifecycle(t *testing.T) {
+	m := model{
+		managementCluster: "alpha",
+		portForwards:      map[string]*portForwardProcess{"Grafana (MC)": {label: "Grafana (MC)", port: "3000:3000"}},
+		portForwardOrder:  []string{mcPaneFocusKey, "Grafana (MC)"},
+		combinedOutput:    newLogBuffer(0, 0),
+		TUIChannel:        make(chan tea.Msg, 1),
+	}
+
+	if _, _, err := createEphemeralPortForward(m, ephemeralSpec{namespace: "monitoring", resource: "service/grafana", port: "3000:3000"}); err == nil {
+		t.Fatal("expected a conflict on local port 3000")
+	}
+
+	m, _, err := createEphemeralPortForward(m, ephemeralSpec{namespace: "monitoring", resource: "service/grafana", port: "3001:3000", ttl: time.Minute})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	label := "Ephemeral monitoring/grafana (MC)"
+	pf, ok := m.portForwards[label]
+	if !ok || !pf.ephemeral || pf.context != "teleport.giantswarm.io-alpha" || m.focusedPanelKey != label {
+		t.Fatalf("ephemeral port-forward not created as expected: %+v", pf)
+	}
+
+	// An outdated expiry does not remove it; the matching one does.
+	m = handleEphemeralExpiredMsg(m, ephemeralExpiredMsg{label: label, expiresAt: pf.expiresAt.Add(-time.Second)})
+	if _, ok := m.portForwards[label]; !ok {
+		t.Fatal("port-forward removed by a stale expiry")
+	}
+	m = handleEphemeralExpiredMsg(m, ephemeralExpiredMsg{label: label, expiresAt: pf.expiresAt})
+	if _, ok := m.portForwards[label]; ok || len(m.portForwardOrder) != 2 || m.focusedPanelKey != "Grafana (MC)" {
+		t.Fatalf("expected the port-forward to be removed, got order %v, focus %q", m.portForwardOrder, m.focusedPanelKey)
+	}
+}
+
+func TestEphemeralPortForwardRemovedBeforeSetup(t *testing.T) {
+	m := model{
+		managementCluster: "alpha",
+		portForwards:      map[string]*portForwardProcess{},
+		combinedOutput:    newLogBuffer(0, 0),
+		TUIChannel:        make(chan tea.Msg, 1),
+	}
+	m, _, err := createEphemeralPortForward(m, ephemeralSpec{namespace: "monitoring", resource: "service/grafana", port: "3001:3000"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	label := "Ephemeral monitoring/grafana (MC)"
+	m = removeEphemeralPortForward(m, label, "removed by user")
+
+	stopChan := make(chan struct{})
+	m, _ = handlePortForwardSetupCompletedMsg(m, portForwardSetupCompletedMsg{label: label, stopChan: stopChan, status: "Initializing..."})
+	select {
+	case <-stopChan:
+	default:
+		t.Fatal("expected the listener of the removed port-forward to be stopped")
+	}
+	if _, ok := m.portForwards[label]; ok {
+		t.Fatal("expected the removed port-forward to stay removed")
+	}
+}
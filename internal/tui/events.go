@@ -0,0 +1,99 @@
+package tui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxEventBatch bounds how many queued messages are folded into one portForwardEventBatchMsg,
+// so a continuous stream of updates cannot starve other messages.
+const maxEventBatch = 256
+
+// portForwardEventBatchMsg carries port-forward status updates that were queued on the TUI channel
+// at the same time, with superseded intermediate states already removed (see coalesceStatusUpdates).
+type portForwardEventBatchMsg struct {
+	updates  []portForwardStatusUpdateMsg // Remaining updates in the order they were sent.
+	next     tea.Msg                      // A non-status message read while draining the channel; nil if none.
+	received int                          // Number of status updates read from the channel for this batch.
+}
+
+// eventStats counts how port-forward status updates were batched, shown in debug mode.
+type eventStats struct {
+	batches   int // Batches of more than one update.
+	coalesced int // Updates delivered as part of such a batch.
+	dropped   int // Updates dropped because a later update superseded them.
+}
+
+// readEvent reads the next message from the TUI channel. Port-forward status updates that are already
+// queued behind it are read as well and delivered as one portForwardEventBatchMsg, so that a burst
+// (e.g. during a mass restart) costs one update and one frame instead of one per event.
+func readEvent(ch chan tea.Msg) tea.Msg {
+	first := <-ch
+	update, ok := first.(portForwardStatusUpdateMsg)
+	if !ok {
+		return first
+	}
+
+	updates := []portForwardStatusUpdateMsg{update}
+	var next tea.Msg
+drain:
+	for len(updates) < maxEventBatch {
+		select {
+		case msg := <-ch:
+			if u, ok := msg.(portForwardStatusUpdateMsg); ok {
+				updates = append(updates, u)
+				continue
+			}
+			next = msg
+			break drain
+		default:
+			break drain
+		}
+	}
+	if len(updates) == 1 && next == nil {
+		return first
+	}
+	return portForwardEventBatchMsg{updates: coalesceStatusUpdates(updates), next: next, received: len(updates)}
+}
+
+// coalesceStatusUpdates drops status updates that only report an intermediate status (no log output,
+// neither an error nor ready) when a later update for the same port-forward replaces that status.
+// Errors, readiness and log output are always kept, so state history and restart counting are unaffected.
+func coalesceStatusUpdates(updates []portForwardStatusUpdateMsg) []portForwardStatusUpdateMsg {
+	superseded := make(map[string]bool)
+	kept := make([]portForwardStatusUpdateMsg, 0, len(updates))
+	// Walk backwards so that superseded tells whether a later update sets a status.
+	for i := len(updates) - 1; i >= 0; i-- {
+		u := updates[i]
+		if superseded[u.label] && u.outputLog == "" && !u.isError && !u.isReady {
+			continue
+		}
+		if u.status != "" {
+			superseded[u.label] = true
+		}
+		kept = append(kept, u)
+	}
+	for i, j := 0, len(kept)-1; i < j; i, j = i+1, j-1 {
+		kept[i], kept[j] = kept[j], kept[i]
+	}
+	return kept
+}
+
+// handlePortForwardEventBatchMsg applies a batch of status updates in order and then the message that ended
+// the batch, if any. Exactly one channel reader is returned, either here or by the handler of msg.next.
+func handlePortForwardEventBatchMsg(m model, msg portForwardEventBatchMsg) (tea.Model, tea.Cmd) {
+	m.events.batches++
+	m.events.coalesced += msg.received
+	m.events.dropped += msg.received - len(msg.updates)
+
+	var cmds []tea.Cmd
+	for _, update := range msg.updates {
+		var cmd tea.Cmd
+		m, cmd = handlePortForwardStatusUpdateMsg(m, update)
+		cmds = append(cmds, cmd)
+	}
+	if msg.next != nil {
+		next, cmd := m.update(msg.next)
+		return next, tea.Batch(append(cmds, cmd)...)
+	}
+	return m, tea.Batch(append(cmds, channelReaderCmd(m.TUIChannel))...)
+}
@@ -0,0 +1,73 @@
+package tui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestReadEventBatchesQueuedStatusUpdates(t *testing.T) {
+	ch := make(chan tea.Msg, 10)
+
+	// A single update is delivered as is.
+	ch <- portForwardStatusUpdateMsg{label: "a", status: "Initializing"}
+	if _, ok := readEvent(ch).(portForwardStatusUpdateMsg); !ok {
+		t.Fatal("expected a single update to be delivered unbatched")
+	}
+
+	ch <- portForwardStatusUpdateMsg{label: "a", status: "Initializing"}
+	ch <- portForwardStatusUpdateMsg{label: "b", status: "Initializing"}
+	ch <- portForwardStatusUpdateMsg{label: "a", status: "Reconnecting"}
+	ch <- portForwardStatusUpdateMsg{label: "a", status: "Error", outputLog: "connection refused", isError: true}
+	ch <- portForwardStatusUpdateMsg{label: "a", status: "Forwarding from 127.0.0.1:3000", isReady: true}
+	ch <- kubectlResultMsg{}
+	ch <- portForwardStatusUpdateMsg{label: "b", status: "Running"}
+
+	batch, ok := readEvent(ch).(portForwardEventBatchMsg)
+	if !ok {
+		t.Fatal("expected queued updates to be batched")
+	}
+	if batch.received != 5 {
+		t.Fatalf("received = %d, want 5", batch.received)
+	}
+	if _, ok := batch.next.(kubectlResultMsg); !ok {
+		t.Fatalf("expected the batch to end at the first other message, got %T", batch.next)
+	}
+	// Only the intermediate statuses of "a" are superseded; "b" has no later update in this batch.
+	want := []string{"b/Initializing", "a/Error", "a/Forwarding from 127.0.0.1:3000"}
+	if len(batch.updates) != len(want) {
+		t.Fatalf("updates = %+v, want %v", batch.updates, want)
+	}
+	for i, u := range batch.updates {
+		if got := u.label + "/" + u.status; got != want[i] {
+			t.Errorf("updates[%d] = %s, want %s", i, got, want[i])
+		}
+	}
+	if len(ch) != 1 {
+		t.Fatalf("expected the update after the other message to stay queued, %d left", len(ch))
+	}
+}
+
+func TestHandlePortForwardEventBatchMsg(t *testing.T) {
+	m := model{
+		portForwards:   map[string]*portForwardProcess{"a": {label: "a", active: true}},
+		combinedOutput: newLogBuffer(0, 0),
+		TUIChannel:     make(chan tea.Msg, 1),
+		render:         newRenderCache(),
+	}
+	msg := portForwardEventBatchMsg{
+		updates:  []portForwardStatusUpdateMsg{{label: "a", status: "Forwarding from 127.0.0.1:3000", isReady: true}},
+		received: 3,
+	}
+	next, cmd := handlePortForwardEventBatchMsg(m, msg)
+	m = next.(model)
+	if !m.portForwards["a"].forwardingEstablished {
+		t.Fatal("expected the batched update to be applied")
+	}
+	if m.events.batches != 1 || m.events.coalesced != 3 || m.events.dropped != 2 {
+		t.Fatalf("unexpected event stats: %+v", m.events)
+	}
+	if cmd == nil {
+		t.Fatal("expected a channel reader to be returned")
+	}
+}
@@ -0,0 +1,145 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/giantswarm/envctl/internal/utils"
+)
+
+// explainHistoryEntries is the number of recent state transitions included in an explanation.
+const explainHistoryEntries = 5
+
+// explainPortForward composes a human-readable explanation of why a port-forward is in its current state.
+// It combines the port-forward's own status and last error with the health of the cluster it depends on,
+// e.g. "stopped because cluster myinstallation failed its health check at 10:32".
+// - m: The current TUI model, used to look up the health of the port-forward's cluster.
+// - pf: The port-forward to explain.
+// Returns the explanation as a list of lines suitable for the activity log.
+func explainPortForward(m model, pf *portForwardProcess) []string {
+	var lines []string
+
+	clusterName, health := m.clusterForPortForward(pf)
+	state, degradedReason := portForwardHealth(m, pf)
+	lines = append(lines, fmt.Sprintf("%s is %s (status: %s).", pf.label, strings.ToLower(state), pf.statusMsg))
+
+	switch state {
+	case pfStateDegraded:
+		lines = append(lines, fmt.Sprintf("Forwarding %s to %s, but degraded: %s.", pf.port, pf.service, degradedReason))
+	case pfStateRunning:
+		lines = append(lines, fmt.Sprintf("Forwarding %s to %s in namespace %s via context %s.", pf.port, pf.service, pf.namespace, pf.context))
+	case pfStateFailed:
+		if pf.lastError != "" {
+			lines = append(lines, fmt.Sprintf("Last error at %s: %s", pf.lastErrorAt.Format("15:04:05"), pf.lastError))
+		} else if pf.err != nil {
+			lines = append(lines, fmt.Sprintf("Setup error: %v", pf.err))
+		}
+	case pfStateStarting:
+		lines = append(lines, "Waiting for the port-forward to report ready.")
+	}
+
+	// The port-forward depends on its cluster being reachable.
+	switch {
+	case health.IsLoading:
+		lines = append(lines, fmt.Sprintf("Depends on cluster %s, whose health is still being checked.", clusterName))
+	case health.StatusError != nil:
+		lines = append(lines, fmt.Sprintf("Depends on cluster %s, which failed its health check at %s: %v",
+			clusterName, health.LastUpdated.Format("15:04:05"), health.StatusError))
+		if utils.IsProxyError(health.StatusError) {
+			lines = append(lines, "Likely cause: the HTTP proxy rejected or failed the connection; check HTTPS_PROXY/NO_PROXY.")
+		} else if state != pfStateRunning && state != pfStateDegraded {
+			lines = append(lines, fmt.Sprintf("Likely cause: cluster %s is unreachable; try 'n' to log in again.", clusterName))
+		}
+	case health.ReadyNodes < health.TotalNodes:
+		lines = append(lines, fmt.Sprintf("Depends on cluster %s, which has only %d/%d nodes ready.", clusterName, health.ReadyNodes, health.TotalNodes))
+	default:
+		lines = append(lines, fmt.Sprintf("Depends on cluster %s, which is healthy (%d/%d nodes).", clusterName, health.ReadyNodes, health.TotalNodes))
+	}
+
+	// How stable the port-forward has been
+	if uptime := portForwardUptime(pf, time.Now()); uptime > 0 {
+		lines = append(lines, fmt.Sprintf("Up for %s (since %s).", formatAge(uptime), pf.runningSince.Format("15:04:05")))
+	}
+	var restarts []string
+	for _, cause := range restartCauses {
+		restarts = append(restarts, fmt.Sprintf("%d %s", pf.restarts[cause], cause))
+	}
+	lines = append(lines, "Restarts: "+strings.Join(restarts, ", ")+".")
+	if failure, ok := lastFailure(pf); ok && state != pfStateFailed {
+		lines = append(lines, fmt.Sprintf("Last failure at %s: %s", failure.At.Format("15:04:05"), failure.Reason))
+	}
+	if pf.correlationID != "" {
+		lines = append(lines, fmt.Sprintf("Last (re)started by operation %s.", pf.correlationID))
+	}
+	if pf.ephemeral {
+		if pf.expiresAt.IsZero() {
+			lines = append(lines, "Ephemeral: removed when the connection changes ('d' removes it now).")
+		} else {
+			lines = append(lines, fmt.Sprintf("Ephemeral: removed at %s ('d' removes it now).", pf.expiresAt.Format("15:04:05")))
+		}
+	}
+	if alert, ok := m.alerts["pf-restarts/"+pf.label]; ok {
+		lines = append(lines, fmt.Sprintf("Alert firing since %s: %s", alert.since.Format("15:04:05"), alert.message))
+	}
+
+	// Show the most recent transitions as a short timeline.
+	if len(pf.history) > 0 {
+		lines = append(lines, "Recent state transitions:")
+		start := len(pf.history) - explainHistoryEntries
+		if start < 0 {
+			start = 0
+		}
+		for _, t := range pf.history[start:] {
+			lines = append(lines, "  "+t.String())
+		}
+	}
+	return lines
+}
+
+// explainCluster composes a human-readable explanation of a cluster pane's health state,
+// including what depends on it and the state of each dependent (see dependencyClosureOf).
+// - m: The current TUI model.
+// - forMC: True to explain the Management Cluster, false for the Workload Cluster.
+func explainCluster(m model, forMC bool) []string {
+	clusterName, health := m.managementCluster, m.MCHealth
+	if !forMC {
+		clusterName, health = m.workloadCluster, m.WCHealth
+	}
+
+	var lines []string
+	switch {
+	case health.IsLoading:
+		lines = append(lines, fmt.Sprintf("Cluster %s: health check in progress.", clusterName))
+	case health.StatusError != nil:
+		lines = append(lines, fmt.Sprintf("Cluster %s: health check failed at %s: %v", clusterName, health.LastUpdated.Format("15:04:05"), health.StatusError))
+		if utils.IsProxyError(health.StatusError) {
+			lines = append(lines, "The failure comes from the HTTP proxy, not the cluster; check HTTPS_PROXY/NO_PROXY.")
+		}
+	case health.ReadyNodes < health.TotalNodes:
+		lines = append(lines, fmt.Sprintf("Cluster %s: degraded, %d/%d nodes ready (checked at %s).", clusterName, health.ReadyNodes, health.TotalNodes, health.LastUpdated.Format("15:04:05")))
+	default:
+		lines = append(lines, fmt.Sprintf("Cluster %s: healthy, %d/%d nodes ready (checked at %s).", clusterName, health.ReadyNodes, health.TotalNodes, health.LastUpdated.Format("15:04:05")))
+	}
+
+	if alert, ok := m.alerts["cluster-unhealthy/"+clusterName]; ok {
+		lines = append(lines, fmt.Sprintf("Alert firing since %s: %s", alert.since.Format("15:04:05"), alert.message))
+	}
+
+	key := mcPaneFocusKey
+	if !forMC {
+		key = wcPaneFocusKey
+	}
+	if closure, ok := dependencyClosureOf(m, key); ok && len(closure.Downstream) > 0 {
+		lines = append(lines, fmt.Sprintf("Depending on it: %s", joinDependencyNodes(closure.Downstream)))
+	}
+	return lines
+}
+
+// clusterForPortForward returns the name and health of the cluster a port-forward targets.
+func (m *model) clusterForPortForward(pf *portForwardProcess) (string, clusterHealthInfo) {
+	if pf.isWC {
+		return m.workloadCluster, m.WCHealth
+	}
+	return m.managementCluster, m.MCHealth
+}
@@ -0,0 +1,105 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExplainPortForwardStates(t *testing.T) {
+	now := time.Now()
+	healthy := clusterHealthInfo{ReadyNodes: 3, TotalNodes: 3, LastUpdated: now}
+	failedHistory := []stateTransition{{At: now.Add(-time.Minute), From: pfStateRunning, To: pfStateFailed, Reason: "connection reset"}}
+
+	tests := []struct {
+		name      string
+		pf        *portForwardProcess
+		mcHealth  clusterHealthInfo
+		wantState string
+		wantLine  string
+	}{
+		{
+			name:      "running",
+			pf:        &portForwardProcess{active: true, forwardingEstablished: true, port: "3000:3000", service: "service/grafana"},
+			mcHealth:  healthy,
+			wantState: pfStateRunning,
+			wantLine:  "Forwarding 3000:3000 to service/grafana",
+		},
+		{
+			name:      "degraded",
+			pf:        &portForwardProcess{active: true, forwardingEstablished: true},
+			mcHealth:  clusterHealthInfo{ReadyNodes: 1, TotalNodes: 3, LastUpdated: now},
+			wantState: pfStateDegraded,
+			wantLine:  "degraded: cluster alpha has only 1/3 nodes ready",
+		},
+		{
+			name:      "failed",
+			pf:        &portForwardProcess{lastError: "connection reset", lastErrorAt: now},
+			mcHealth:  healthy,
+			wantState: pfStateFailed,
+			wantLine:  "Last error at",
+		},
+		{
+			name:      "setup failed",
+			pf:        &portForwardProcess{err: errors.New("no ready pods")},
+			mcHealth:  healthy,
+			wantState: pfStateFailed,
+			wantLine:  "Setup error: no ready pods",
+		},
+		{
+			name:      "starting",
+			pf:        &portForwardProcess{active: true},
+			mcHealth:  healthy,
+			wantState: pfStateStarting,
+			wantLine:  "Waiting for the port-forward to report ready.",
+		},
+		{
+			name:      "stopped",
+			pf:        &portForwardProcess{},
+			mcHealth:  healthy,
+			wantState: pfStateStopped,
+			wantLine:  "Depends on cluster alpha, which is healthy",
+		},
+		{
+			name:      "recovered",
+			pf:        &portForwardProcess{active: true, forwardingEstablished: true, history: failedHistory},
+			mcHealth:  healthy,
+			wantState: pfStateRunning,
+			wantLine:  "Last failure at " + failedHistory[0].At.Format("15:04:05") + ": connection reset",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.pf.label = "Grafana (MC)"
+			m := model{managementCluster: "alpha", MCHealth: tt.mcHealth}
+			lines := explainPortForward(m, tt.pf)
+			if !strings.HasPrefix(lines[0], "Grafana (MC) is "+strings.ToLower(tt.wantState)+" ") {
+				t.Errorf("got %q, want state %s", lines[0], tt.wantState)
+			}
+			if !strings.Contains(strings.Join(lines, "\n"), tt.wantLine) {
+				t.Errorf("expected a line containing %q, got:\n%s", tt.wantLine, strings.Join(lines, "\n"))
+			}
+		})
+	}
+}
+
+func TestRecoveredPortForwardIsNotExplainedAsFailed(t *testing.T) {
+	m := model{
+		managementCluster: "alpha",
+		portForwards:      map[string]*portForwardProcess{},
+		combinedOutput:    newLogBuffer(0, 0),
+	}
+	pf := &portForwardProcess{label: "Grafana (MC)", active: true}
+	m.portForwards[pf.label] = pf
+
+	m, _ = handlePortForwardStatusUpdateMsg(m, portForwardStatusUpdateMsg{label: pf.label, status: "Error", outputLog: "connection reset", isError: true})
+	if got := portForwardState(pf); got != pfStateFailed {
+		t.Fatalf("expected failed, got %s", got)
+	}
+	m, _ = handlePortForwardSetupCompletedMsg(m, portForwardSetupCompletedMsg{label: pf.label, stopChan: make(chan struct{}), status: "Initializing..."})
+	stopPortForward(&m, pf, "stopped by user")
+	if got := portForwardState(pf); got != pfStateStopped {
+		t.Errorf("expected a recovered and then stopped port-forward to be stopped, got %s", got)
+	}
+}
@@ -7,8 +7,8 @@ import (
 
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
-	// Assuming utils is in "github.com/giantswarm/envctl/internal/utils" based on model.go
-	// We might need to adjust this if utils is not directly accessible or causes import cycle
+
+	"github.com/giantswarm/envctl/internal/utils"
 )
 
 // handleWindowSizeMsg updates the model with the new terminal dimensions when the window is resized.
@@ -22,8 +22,9 @@ func handleWindowSizeMsg(m model, msg tea.WindowSizeMsg) (model, tea.Cmd) {
 
 // handleKeyMsgInputMode processes key presses when the TUI is in the 'new connection input' mode.
 // It handles keys for submitting input (Enter, Ctrl+S), canceling (Esc), and autocompletion (Tab).
-// - For Enter/Ctrl+S: If entering MC name, it stores it and moves to WC input. If entering WC name, it submits both.
-// - For Esc: Cancels the input mode and resets state.
+// - For Enter/Ctrl+S: Moves from MC to WC input, then shows the impact of the switch for confirmation, then submits.
+// - For 'y': Confirms the switch during the confirmation step (skipped entirely with auto-confirm).
+// - For Esc: Cancels the input mode (or the pending switch) and resets state.
 // - For Tab: Attempts to autocomplete the current input based on fetched cluster lists.
 // Other keys are passed to the textinput component for standard text editing.
 func handleKeyMsgInputMode(m model, keyMsg tea.KeyMsg) (model, tea.Cmd) {
@@ -41,14 +42,9 @@ func handleKeyMsgInputMode(m model, keyMsg tea.KeyMsg) (model, tea.Cmd) {
 			m.newConnectionInput.Focus()
 			return m, nil
 		} else if m.currentInputStep == wcInputStep {
-			wcName := m.newConnectionInput.Value()
-			m.isConnectingNew = false
-			m.newConnectionInput.Blur()
-			m.newConnectionInput.Reset()
-			if len(m.portForwardOrder) > 0 {
-				m.focusedPanelKey = m.portForwardOrder[0]
-			}
-			return m, func() tea.Msg { return submitNewConnectionMsg{mc: m.stashedMcName, wc: wcName} }
+			return requestNewConnection(m, m.newConnectionInput.Value())
+		} else if m.currentInputStep == confirmInputStep {
+			return confirmNewConnection(m)
 		}
 
 	case "enter": // Confirm MC input and move to WC, or submit WC input
@@ -64,17 +60,16 @@ func handleKeyMsgInputMode(m model, keyMsg tea.KeyMsg) (model, tea.Cmd) {
 			m.newConnectionInput.Focus()
 			return m, nil
 		} else if m.currentInputStep == wcInputStep {
-			wcName := m.newConnectionInput.Value()
-			m.isConnectingNew = false
-			m.newConnectionInput.Blur()
-			m.newConnectionInput.Reset()
-			if len(m.portForwardOrder) > 0 {
-				m.focusedPanelKey = m.portForwardOrder[0]
-			}
-			return m, func() tea.Msg { return submitNewConnectionMsg{mc: m.stashedMcName, wc: wcName} }
+			return requestNewConnection(m, m.newConnectionInput.Value())
+		} else if m.currentInputStep == confirmInputStep {
+			return confirmNewConnection(m)
 		}
 
 	case "esc": // Cancel new connection input
+		if m.currentInputStep == confirmInputStep {
+			m.combinedOutput.Append("[SYSTEM] Connection switch cancelled.")
+		}
+		m.pendingImpact, m.unsatisfiable = nil, nil
 		m.isConnectingNew = false
 		m.newConnectionInput.Blur()
 		m.newConnectionInput.Reset()
@@ -85,6 +80,14 @@ func handleKeyMsgInputMode(m model, keyMsg tea.KeyMsg) (model, tea.Cmd) {
 		}
 		return m, nil
 
+	case "y": // Confirm the switch during the confirmation step; otherwise a regular character
+		if m.currentInputStep == confirmInputStep {
+			return confirmNewConnection(m)
+		}
+		var inputCmd tea.Cmd
+		m.newConnectionInput, inputCmd = m.newConnectionInput.Update(keyMsg)
+		return m, inputCmd
+
 	case "tab": // Autocompletion
 		currentInput := m.newConnectionInput.Value()
 		if m.clusterInfo != nil && currentInput != "" {
@@ -113,6 +116,10 @@ func handleKeyMsgInputMode(m model, keyMsg tea.KeyMsg) (model, tea.Cmd) {
 		return m, nil // Tab consumed
 
 	default:
+		// No text is edited while confirming
+		if m.currentInputStep == confirmInputStep {
+			return m, nil
+		}
 		// Let the textinput handle other keys
 		var inputCmd tea.Cmd
 		m.newConnectionInput, inputCmd = m.newConnectionInput.Update(keyMsg)
@@ -121,6 +128,35 @@ func handleKeyMsgInputMode(m model, keyMsg tea.KeyMsg) (model, tea.Cmd) {
 	return m, nil // Should not be reached
 }
 
+// requestNewConnection is called once the WC input is submitted. Unless auto-confirm is enabled,
+// it computes the impact of switching to the new connection and moves to the confirmation step
+// instead of connecting right away.
+func requestNewConnection(m model, wcName string) (model, tea.Cmd) {
+	m.pendingConnection = submitNewConnectionMsg{mc: m.stashedMcName, wc: wcName, correlationID: newCorrelationID()}
+	if m.autoConfirm {
+		return confirmNewConnection(m)
+	}
+	m.pendingImpact = planConnectionSwitch(m, m.stashedMcName, wcName)
+	m.unsatisfiable = unsatisfiableAfterSwitch(m, m.stashedMcName, wcName)
+	// The input stays focused so key presses keep being routed to handleKeyMsgInputMode.
+	m.currentInputStep = confirmInputStep
+	return m, nil
+}
+
+// confirmNewConnection leaves input mode and submits the pending connection.
+func confirmNewConnection(m model) (model, tea.Cmd) {
+	m.isConnectingNew = false
+	m.currentInputStep = mcInputStep
+	m.pendingImpact, m.unsatisfiable = nil, nil
+	m.newConnectionInput.Blur()
+	m.newConnectionInput.Reset()
+	if len(m.portForwardOrder) > 0 {
+		m.focusedPanelKey = m.portForwardOrder[0]
+	}
+	msg := m.pendingConnection
+	return m, func() tea.Msg { return msg }
+}
+
 // handleKeyMsgGlobal processes global key presses when not in a specific input mode.
 // It handles actions like:
 // - Quitting the application ('q', Ctrl+C): Closes active port-forward stop channels and sends tea.Quit.
@@ -128,6 +164,12 @@ func handleKeyMsgInputMode(m model, keyMsg tea.KeyMsg) (model, tea.Cmd) {
 // - Navigating panels (Tab, Shift+Tab, 'j'/Down, 'k'/Up): Cycles focus through UI panels.
 // - Restarting a focused port-forward ('r'): Stops and starts the selected port-forward process.
 // - Switching Kubernetes context ('s'): Attempts to switch to the context of the focused MC or WC pane.
+// - Explaining the focused panel's state ('x'): Writes an explanation to the activity log.
+// - Triaging the most recent failure ('X'): Writes its likely causes and fixes to the activity log.
+// - Pausing or resuming health checking ('P'): Suspends health checks, alerts and automatic reconciliation for a while.
+// - Opening the cluster picker ('p'): Shows a searchable list of clusters to connect to.
+// - Opening the table view ('T'): Lists port-forwards in a sortable, filterable table with bulk actions.
+// - Opening the kubectl pane (':'): Runs kubectl against the focused cluster, if enabled.
 // - Toggling Log Overlay ('L') is handled in model.Update's KeyMsg block.
 func handleKeyMsgGlobal(m model, keyMsg tea.KeyMsg, existingCmds []tea.Cmd) (model, tea.Cmd) {
 	var cmds = existingCmds // Start with existing commands
@@ -154,6 +196,15 @@ func handleKeyMsgGlobal(m model, keyMsg tea.KeyMsg, existingCmds []tea.Cmd) (mod
 		return m, nil
 	}
 
+	// In read-only mode, actions that change state are refused.
+	if m.readOnly {
+		switch keyMsg.String() {
+		case "n", "p", "r", "R", "s", "f", "d", ":": // kubectl could modify the clusters
+			m.combinedOutput.Append(fmt.Sprintf("[SYSTEM] Read-only mode: '%s' is disabled. %s", keyMsg.String(), m.readOnlyReason))
+			return m, nil
+		}
+	}
+
 	switch keyMsg.String() {
 	case "ctrl+c", "q":
 		m.quitting = true
@@ -180,6 +231,36 @@ func handleKeyMsgGlobal(m model, keyMsg tea.KeyMsg, existingCmds []tea.Cmd) (mod
 			return m, textinput.Blink
 		}
 
+	case ":": // Open the kubectl pane
+		if !m.kubectlEnabled {
+			m.combinedOutput.Append("[SYSTEM] The kubectl pane is disabled. Start envctl with --enable-kubectl-pane to use it.")
+			return m, nil
+		}
+		m.kubectl.visible = true
+		m.kubectl.input.Focus()
+		resizeKubectlPane(&m)
+		return m, textinput.Blink
+
+	case "f": // Create an ephemeral port-forward on the focused cluster
+		m.ephemeral.visible = true
+		m.ephemeral.input.Focus()
+		return m, textinput.Blink
+
+	case "d": // Remove the focused ephemeral port-forward
+		if pf, ok := m.portForwards[m.focusedPanelKey]; ok && pf.ephemeral {
+			return removeEphemeralPortForward(m, pf.label, "removed by user"), nil
+		}
+		return m, nil
+
+	case "T": // Open the port-forward table view
+		m.table.visible = true
+		m.table.cursor = 0
+		return m, nil
+
+	case "p": // Open the cluster picker
+		m.picker = clusterPicker{visible: true}
+		return m, fetchClusterListCmd() // Refresh clusters, login state and last-used times
+
 	case "tab": // Panel focus
 		if len(m.portForwardOrder) > 0 {
 			currentIndex := -1
@@ -252,41 +333,55 @@ func handleKeyMsgGlobal(m model, keyMsg tea.KeyMsg, existingCmds []tea.Cmd) (mod
 		}
 		return m, nil
 
+	case "]": // Next page of port-forward panels
+		return pagePortForwards(m, 1), nil
+
+	case "[": // Previous page of port-forward panels
+		return pagePortForwards(m, -1), nil
+
 	case "r": // Restart focused port-forward
 		if m.focusedPanelKey != "" {
 			if pf, ok := m.portForwards[m.focusedPanelKey]; ok {
-				// Stop the existing port-forward if it's running
-				if pf.stopChan != nil {
-					m.combinedOutput = append(m.combinedOutput, fmt.Sprintf("[%s] Sending stop signal...", pf.label))
-					close(pf.stopChan)
-					pf.stopChan = nil
-				}
-
-				// Update UI immediately to reflect that a restart is in progress
-				pf.statusMsg = "Restarting..."
-				pf.output = []string{} // Clear old specific output for this PF
-				pf.err = nil
-				pf.active = true // It is attempting to become active
-				pf.forwardingEstablished = false
-				// Fields like cmd, stdout, stderr, stdoutClosed, stderrClosed are removed from portForwardProcess
-
-				m.combinedOutput = append(m.combinedOutput, fmt.Sprintf("[%s] Attempting restart...", pf.label))
-				if len(m.combinedOutput) > maxCombinedOutputLines {
-					m.combinedOutput = m.combinedOutput[len(m.combinedOutput)-maxCombinedOutputLines:]
-				}
-
-				// Start the new port-forward using startPortForwardCmd
-				if m.TUIChannel != nil {
-					restartCmd := startPortForwardCmd(pf.label, pf.context, pf.namespace, pf.service, pf.port, m.TUIChannel)
+				// Each manual restart is a new root operation.
+				if restartCmd := restartPortForward(&m, pf, restartManual, newCorrelationID(), "manual restart"); restartCmd != nil {
 					cmds = append(cmds, restartCmd)
-				} else {
-					m.combinedOutput = append(m.combinedOutput, fmt.Sprintf("[%s ERROR] TUIChannel is nil. Cannot restart.", pf.label))
-					pf.statusMsg = "Restart Failed (Internal Error)"
-					pf.active = false
 				}
 			}
 		}
 
+	case "R": // Restart the focused cluster and every port-forward depending on it
+		return requestRestartTree(m)
+
+	case "P": // Pause or resume health checking
+		return toggleHealthPause(m)
+
+	case "m": // Start or end maintenance of the focused cluster or port-forward
+		return toggleMaintenance(m, focusedMaintenanceScope(m))
+
+	case "M": // Start or end maintenance of everything
+		return toggleMaintenance(m, maintenanceAll)
+
+	case "x": // Explain the state of the focused panel
+		var explanation []string
+		if m.focusedPanelKey == mcPaneFocusKey && m.managementCluster != "" {
+			explanation = explainCluster(m, true)
+		} else if m.focusedPanelKey == wcPaneFocusKey && m.workloadCluster != "" {
+			explanation = explainCluster(m, false)
+		} else if pf, ok := m.portForwards[m.focusedPanelKey]; ok {
+			explanation = explainPortForward(m, pf)
+		}
+		if len(explanation) == 0 {
+			m.combinedOutput.Append("[EXPLAIN] Nothing to explain: focus a cluster pane or port-forward panel.")
+		}
+		for _, line := range explanation {
+			m.combinedOutput.Append("[EXPLAIN] " + line)
+		}
+
+	case "X": // Triage the most recent failure
+		for _, line := range triageLastFailure(m) {
+			m.combinedOutput.Append("[TRIAGE] " + line)
+		}
+
 	case "s": // Switch kubectl context to focused MC/WC pane
 		var targetContextToSwitch string
 		var clusterIdentifier string // Renamed from clusterShortNameForContext
@@ -305,13 +400,11 @@ func handleKeyMsgGlobal(m model, keyMsg tea.KeyMsg, existingCmds []tea.Cmd) (mod
 			// the part of the context name *after* "teleport.giantswarm.io-".
 			// So, we always prepend the prefix here.
 			targetContextToSwitch = "teleport.giantswarm.io-" + clusterIdentifier
-			m.combinedOutput = append(m.combinedOutput, fmt.Sprintf("[SYSTEM] Attempting to switch kubectl context to: %s (Pane: %s)", targetContextToSwitch, paneNameForLog))
-			cmds = append(cmds, performSwitchKubeContextCmd(targetContextToSwitch))
+			correlationID := newCorrelationID()
+			m.combinedOutput.Append(fmt.Sprintf("[SYSTEM] %sAttempting to switch kubectl context to: %s (Pane: %s)", correlationTag(correlationID), targetContextToSwitch, paneNameForLog))
+			cmds = append(cmds, performSwitchKubeContextCmd(targetContextToSwitch, correlationID))
 		} else {
-			m.combinedOutput = append(m.combinedOutput, "[SYSTEM] Cannot switch context: Focus a valid MC/WC pane with a defined cluster name.")
-		}
-		if len(m.combinedOutput) > maxCombinedOutputLines {
-			m.combinedOutput = m.combinedOutput[len(m.combinedOutput)-maxCombinedOutputLines:]
+			m.combinedOutput.Append("[SYSTEM] Cannot switch context: Focus a valid MC/WC pane with a defined cluster name.")
 		}
 	}
 	return m, tea.Batch(cmds...)
@@ -322,13 +415,10 @@ func handleKeyMsgGlobal(m model, keyMsg tea.KeyMsg, existingCmds []tea.Cmd) (mod
 func handleKubeContextResultMsg(m model, msg kubeContextResultMsg) model {
 	if msg.err != nil {
 		m.currentKubeContext = "Error fetching context"
-		m.combinedOutput = append(m.combinedOutput, fmt.Sprintf("[SYSTEM] Error getting current kube context: %s", msg.err.Error()))
+		m.combinedOutput.Append(fmt.Sprintf("[SYSTEM] Error getting current kube context: %s", msg.err.Error()))
 	} else {
 		m.currentKubeContext = msg.context
-		m.combinedOutput = append(m.combinedOutput, fmt.Sprintf("[SYSTEM] Current kubectl context: %s", msg.context))
-	}
-	if len(m.combinedOutput) > maxCombinedOutputLines {
-		m.combinedOutput = m.combinedOutput[len(m.combinedOutput)-maxCombinedOutputLines:]
+		m.combinedOutput.Append(fmt.Sprintf("[SYSTEM] Current kubectl context: %s", msg.context))
 	}
 	return m
 }
@@ -338,12 +428,20 @@ func handleKubeContextResultMsg(m model, msg kubeContextResultMsg) model {
 // It also re-schedules the next health update tick.
 func handleRequestClusterHealthUpdate(m model) (model, tea.Cmd) {
 	var cmds []tea.Cmd
-	logMsg := fmt.Sprintf("[SYSTEM] Requesting cluster health updates at %s", time.Now().Format("15:04:05"))
-	m.combinedOutput = append(m.combinedOutput, logMsg)
-	if len(m.combinedOutput) > maxCombinedOutputLines {
-		m.combinedOutput = m.combinedOutput[len(m.combinedOutput)-maxCombinedOutputLines:]
+	// Re-tick for next update
+	cmds = append(cmds, tea.Tick(healthUpdateInterval, func(t time.Time) tea.Msg {
+		return requestClusterHealthUpdate{}
+	}))
+	if healthChecksPaused(m, time.Now()) {
+		return m, tea.Batch(cmds...)
 	}
 
+	logMsg := fmt.Sprintf("[SYSTEM] Requesting cluster health updates at %s", time.Now().Format("15:04:05"))
+	m.combinedOutput.Append(logMsg)
+
+	// Sample the environment health from the previous round of checks before starting a new one
+	recordHealthSample(&m, time.Now())
+
 	if m.managementCluster != "" {
 		m.MCHealth.IsLoading = true
 		mcIdentifier := m.getManagementClusterContextIdentifier()
@@ -358,10 +456,6 @@ func handleRequestClusterHealthUpdate(m model) (model, tea.Cmd) {
 			cmds = append(cmds, fetchNodeStatusCmd(wcIdentifier, false, m.workloadCluster))
 		}
 	}
-	// Re-tick for next update
-	cmds = append(cmds, tea.Tick(healthUpdateInterval, func(t time.Time) tea.Msg {
-		return requestClusterHealthUpdate{}
-	}))
 	return m, tea.Batch(cmds...)
 }
 
@@ -379,10 +473,7 @@ func handleNodeStatusMsg(m model, msg nodeStatusMsg) model {
 		targetHealth = &m.WCHealth
 		clusterNameForLog = m.workloadCluster
 	} else {
-		m.combinedOutput = append(m.combinedOutput, fmt.Sprintf("[HEALTH STALE/MISMATCH] Received status for '%s' (isMC: %v), current MC: '%s', WC: '%s'. Discarding.", msg.clusterShortName, msg.forMC, m.managementCluster, m.workloadCluster))
-		if len(m.combinedOutput) > maxCombinedOutputLines {
-			m.combinedOutput = m.combinedOutput[len(m.combinedOutput)-maxCombinedOutputLines:]
-		}
+		m.combinedOutput.Append(fmt.Sprintf("[HEALTH STALE/MISMATCH] Received status for '%s' (isMC: %v), current MC: '%s', WC: '%s'. Discarding.", msg.clusterShortName, msg.forMC, m.managementCluster, m.workloadCluster))
 		return m // No further processing for this stale/mismatched message
 	}
 
@@ -392,15 +483,16 @@ func handleNodeStatusMsg(m model, msg nodeStatusMsg) model {
 		targetHealth.StatusError = msg.err
 		targetHealth.ReadyNodes = 0
 		targetHealth.TotalNodes = 0
-		m.combinedOutput = append(m.combinedOutput, fmt.Sprintf("[HEALTH %s] Error: %s", clusterNameForLog, msg.err.Error()))
+		if utils.IsProxyError(msg.err) {
+			m.combinedOutput.Append(fmt.Sprintf("[HEALTH %s] Proxy error: %s", clusterNameForLog, msg.err.Error()))
+		} else {
+			m.combinedOutput.Append(fmt.Sprintf("[HEALTH %s] Error: %s", clusterNameForLog, msg.err.Error()))
+		}
 	} else {
 		targetHealth.StatusError = nil
 		targetHealth.ReadyNodes = msg.readyNodes
 		targetHealth.TotalNodes = msg.totalNodes
-		m.combinedOutput = append(m.combinedOutput, fmt.Sprintf("[HEALTH %s] Nodes: %d/%d", clusterNameForLog, msg.readyNodes, msg.totalNodes))
-	}
-	if len(m.combinedOutput) > maxCombinedOutputLines {
-		m.combinedOutput = m.combinedOutput[len(m.combinedOutput)-maxCombinedOutputLines:]
+		m.combinedOutput.Append(fmt.Sprintf("[HEALTH %s] Nodes: %d/%d", clusterNameForLog, msg.readyNodes, msg.totalNodes))
 	}
 	return m
 }
@@ -410,10 +502,15 @@ func handleNodeStatusMsg(m model, msg nodeStatusMsg) model {
 // If fetching fails, an error is logged.
 func handleClusterListResultMsg(m model, msg clusterListResultMsg) model {
 	if msg.err != nil {
-		m.combinedOutput = append(m.combinedOutput, fmt.Sprintf("[SYSTEM ERROR] Failed to fetch cluster list: %v", msg.err))
+		m.combinedOutput.Append(fmt.Sprintf("[SYSTEM ERROR] Failed to fetch cluster list: %v", msg.err))
 	} else {
 		m.clusterInfo = msg.info
-		// m.combinedOutput = append(m.combinedOutput, "[SYSTEM] Cluster list fetched for autocompletion.") // Optional: too verbose?
+		m.recentClusters = msg.recent
+		m.loggedInContexts = make(map[string]bool, len(msg.kubeContexts))
+		for _, ctx := range msg.kubeContexts {
+			m.loggedInContexts[ctx] = true
+		}
+		// m.combinedOutput.Append("[SYSTEM] Cluster list fetched for autocompletion.") // Optional: too verbose?
 	}
 	return m
 }
@@ -423,10 +520,11 @@ func handleClusterListResultMsg(m model, msg clusterListResultMsg) model {
 // If failed, it logs the error.
 func handleKubeContextSwitchedMsg(m model, msg kubeContextSwitchedMsg) (model, tea.Cmd) {
 	var cmds []tea.Cmd
+	tag := correlationTag(msg.correlationID)
 	if msg.err != nil {
-		m.combinedOutput = append(m.combinedOutput, fmt.Sprintf("[SYSTEM] Failed to switch kubectl context to '%s': %s", msg.TargetContext, msg.err.Error()))
+		m.combinedOutput.Append(fmt.Sprintf("[SYSTEM] %sFailed to switch kubectl context to '%s': %s", tag, msg.TargetContext, msg.err.Error()))
 	} else {
-		m.combinedOutput = append(m.combinedOutput, fmt.Sprintf("[SYSTEM] Successfully switched kubectl context. Target was: %s", msg.TargetContext))
+		m.combinedOutput.Append(fmt.Sprintf("[SYSTEM] %sSuccessfully switched kubectl context. Target was: %s", tag, msg.TargetContext))
 		cmds = append(cmds, getCurrentKubeContextCmd())
 		if m.managementCluster != "" {
 			m.MCHealth.IsLoading = true
@@ -443,8 +541,5 @@ func handleKubeContextSwitchedMsg(m model, msg kubeContextSwitchedMsg) (model, t
 			}
 		}
 	}
-	if len(m.combinedOutput) > maxCombinedOutputLines {
-		m.combinedOutput = m.combinedOutput[len(m.combinedOutput)-maxCombinedOutputLines:]
-	}
 	return m, tea.Batch(cmds...)
 }
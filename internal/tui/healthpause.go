@@ -0,0 +1,58 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// defaultHealthPauseDuration is how long health checking stays paused when Options.HealthPauseDuration is not set.
+const defaultHealthPauseDuration = 30 * time.Minute
+
+// healthResumeMsg resumes health checking when a pause expires.
+// until identifies the pause, so that the expiry of a pause that was already ended or extended is ignored.
+type healthResumeMsg struct {
+	until time.Time
+}
+
+// healthChecksPaused reports whether health checking is paused at the given time.
+func healthChecksPaused(m model, now time.Time) bool {
+	return !m.healthPausedUntil.IsZero() && now.Before(m.healthPausedUntil)
+}
+
+// pauseHealthChecks suspends periodic cluster health checks, alert evaluation and the automatic
+// reconciliation after sleep/wake or network changes for the given duration, e.g. while a process
+// behind a port-forward is stopped at a breakpoint. Health checking resumes automatically afterwards.
+func pauseHealthChecks(m model, d time.Duration) (model, tea.Cmd) {
+	until := time.Now().Add(d)
+	m.healthPausedUntil = until
+	m.combinedOutput.Append(fmt.Sprintf("[SYSTEM] Health checks paused until %s ('P' resumes them now).", until.Format("15:04:05")))
+	return m, tea.Tick(d, func(time.Time) tea.Msg { return healthResumeMsg{until: until} })
+}
+
+// resumeHealthChecks ends a pause and requests a health update right away, so that anything
+// that broke while paused is noticed immediately instead of at the next periodic check.
+func resumeHealthChecks(m model, reason string) (model, tea.Cmd) {
+	m.healthPausedUntil = time.Time{}
+	m.combinedOutput.Append(fmt.Sprintf("[SYSTEM] Health checks resumed (%s).", reason))
+	// Unhealthy timestamps from before the pause are stale; alerts must not fire on them right away.
+	m.unhealthySince = make(map[string]time.Time)
+	return m, func() tea.Msg { return requestClusterHealthUpdate{} }
+}
+
+// toggleHealthPause pauses health checking for the configured duration, or resumes it if it is paused.
+func toggleHealthPause(m model) (model, tea.Cmd) {
+	if healthChecksPaused(m, time.Now()) {
+		return resumeHealthChecks(m, "resumed manually")
+	}
+	return pauseHealthChecks(m, m.healthPauseDuration)
+}
+
+// handleHealthResumeMsg resumes health checking when the pause identified by msg expires.
+func handleHealthResumeMsg(m model, msg healthResumeMsg) (model, tea.Cmd) {
+	if m.healthPausedUntil.IsZero() || !m.healthPausedUntil.Equal(msg.until) {
+		return m, nil // The pause was ended or replaced in the meantime.
+	}
+	return resumeHealthChecks(m, "pause expired")
+}
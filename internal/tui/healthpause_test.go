@@ -0,0 +1,52 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHealthPauseSuspendsChecksAndResumes(t *testing.T) {
+	m := model{
+		managementCluster:   "alpha",
+		combinedOutput:      newLogBuffer(0, 0),
+		healthPauseDuration: time.Hour,
+		unhealthySince:      map[string]time.Time{"alpha": time.Now().Add(-time.Hour)},
+	}
+
+	m, _ = toggleHealthPause(m)
+	if !healthChecksPaused(m, time.Now()) {
+		t.Fatal("expected health checks to be paused")
+	}
+	until := m.healthPausedUntil
+
+	// No health check is requested while paused.
+	m.MCHealth.IsLoading = false
+	m, _ = handleRequestClusterHealthUpdate(m)
+	if m.MCHealth.IsLoading || len(m.healthSamples) != 0 {
+		t.Fatal("health check ran while paused")
+	}
+
+	// A sleep/wake event is logged but not reconciled.
+	m.lastWakeCheck = time.Now().Add(-time.Hour)
+	m, _ = handleWakeCheckMsg(m, wakeCheckMsg{at: time.Now()})
+	if !strings.Contains(m.combinedOutput.Last(), "not reconciling") {
+		t.Fatalf("expected reconciliation to be skipped, got %q", m.combinedOutput.Last())
+	}
+
+	// The expiry of an older pause is ignored; the matching one resumes.
+	m, _ = handleHealthResumeMsg(m, healthResumeMsg{until: until.Add(-time.Minute)})
+	if !healthChecksPaused(m, time.Now()) {
+		t.Fatal("stale expiry resumed health checks")
+	}
+	m, cmd := handleHealthResumeMsg(m, healthResumeMsg{until: until})
+	if healthChecksPaused(m, time.Now()) || cmd == nil {
+		t.Fatal("expected health checks to resume with an immediate update")
+	}
+	if _, ok := cmd().(requestClusterHealthUpdate); !ok {
+		t.Fatal("expected an immediate health update request")
+	}
+	if len(m.unhealthySince) != 0 {
+		t.Fatal("expected unhealthy timestamps from before the pause to be dropped")
+	}
+}
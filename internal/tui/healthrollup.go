@@ -0,0 +1,144 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// healthRollupRetention bounds how long environment health samples are kept.
+	healthRollupRetention = time.Hour
+	// healthRollupShortWindow is the recent window compared against the one before it to derive the trend.
+	healthRollupShortWindow = 5 * time.Minute
+	// healthTrendThreshold is the change in percentage points needed to report an improving or degrading trend.
+	healthTrendThreshold = 1.0
+)
+
+// healthSample records how many critical services were healthy at one point in time.
+// Critical services are the connected clusters and all port-forwards that are meant to run.
+// Degraded services are counted separately and weighted with degradedHealthWeight in the rollup.
+// Stopped port-forwards (stopped by the user, or never started in read-only mode) are neither
+// healthy nor unhealthy; they are counted in their own bucket and left out of Total.
+type healthSample struct {
+	At       time.Time
+	Healthy  int
+	Degraded int
+	Stopped  int
+	Total    int
+}
+
+// takeHealthSample counts the healthy critical services of the environment.
+// A cluster is healthy if its last health check succeeded with all nodes ready; a port-forward if it is forwarding.
+// Clusters whose health has not been checked yet are left out, stopped port-forwards are counted as Stopped.
+func takeHealthSample(m model, now time.Time) healthSample {
+	sample := healthSample{At: now}
+	clusters := []struct {
+		name   string
+		health clusterHealthInfo
+	}{{m.managementCluster, m.MCHealth}, {m.workloadCluster, m.WCHealth}}
+	for _, c := range clusters {
+		if c.name == "" || c.health.LastUpdated.IsZero() {
+			continue
+		}
+		sample.Total++
+		if c.health.StatusError == nil && c.health.TotalNodes > 0 {
+			if c.health.ReadyNodes == c.health.TotalNodes {
+				sample.Healthy++
+			} else if c.health.ReadyNodes > 0 {
+				sample.Degraded++
+			}
+		}
+	}
+	for _, label := range m.portForwardOrder {
+		if pf, ok := m.portForwards[label]; ok {
+			state, _ := portForwardHealth(m, pf)
+			if state == pfStateStopped {
+				sample.Stopped++
+				continue
+			}
+			sample.Total++
+			switch state {
+			case pfStateRunning:
+				sample.Healthy++
+			case pfStateDegraded:
+				sample.Degraded++
+			}
+		}
+	}
+	return sample
+}
+
+// recordHealthSample appends the current environment health to the model's samples
+// and drops samples older than healthRollupRetention.
+func recordHealthSample(m *model, now time.Time) {
+	sample := takeHealthSample(*m, now)
+	if sample.Total == 0 {
+		return
+	}
+	m.healthSamples = append(m.healthSamples, sample)
+	cutoff := now.Add(-healthRollupRetention)
+	i := 0
+	for i < len(m.healthSamples) && m.healthSamples[i].At.Before(cutoff) {
+		i++
+	}
+	m.healthSamples = m.healthSamples[i:]
+}
+
+// healthRollup returns the percentage of healthy critical services across all samples taken in [from, to).
+// ok is false if there are no samples in the window.
+func healthRollup(samples []healthSample, from, to time.Time) (pct float64, ok bool) {
+	var healthy float64
+	var total int
+	for _, s := range samples {
+		if s.At.Before(from) || !s.At.Before(to) {
+			continue
+		}
+		healthy += float64(s.Healthy) + degradedHealthWeight*float64(s.Degraded)
+		total += s.Total
+	}
+	if total == 0 {
+		return 0, false
+	}
+	return 100 * healthy / float64(total), true
+}
+
+// healthTrend compares the most recent short window against the one before it.
+// Returns "↑" (improving), "↓" (degrading), "→" (stable) or "" if there is not enough data.
+func healthTrend(samples []healthSample, now time.Time) string {
+	end := now.Add(time.Nanosecond) // Include a sample taken exactly now.
+	recent, okRecent := healthRollup(samples, now.Add(-healthRollupShortWindow), end)
+	previous, okPrevious := healthRollup(samples, now.Add(-2*healthRollupShortWindow), now.Add(-healthRollupShortWindow))
+	if !okRecent || !okPrevious {
+		return ""
+	}
+	switch {
+	case recent-previous >= healthTrendThreshold:
+		return "↑"
+	case previous-recent >= healthTrendThreshold:
+		return "↓"
+	default:
+		return "→"
+	}
+}
+
+// renderHealthRollup formats the environment health for the header, e.g. "Env health 100% (5m) 97% (1h) ↑",
+// followed by the number of stopped port-forwards if there are any, e.g. "| 3 stopped".
+// Windows are measured back from the latest sample, so rendering does not depend on the wall clock.
+// Returns an empty string until the first sample has been recorded.
+func renderHealthRollup(m model) string {
+	if len(m.healthSamples) == 0 {
+		return ""
+	}
+	now := m.healthSamples[len(m.healthSamples)-1].At
+	end := now.Add(time.Nanosecond)
+	short, _ := healthRollup(m.healthSamples, now.Add(-healthRollupShortWindow), end)
+	long, _ := healthRollup(m.healthSamples, now.Add(-healthRollupRetention), end)
+	s := fmt.Sprintf("Env health %.0f%% (5m) %.0f%% (1h)", short, long)
+	if trend := healthTrend(m.healthSamples, now); trend != "" {
+		s += " " + trend
+	}
+	if stopped := m.healthSamples[len(m.healthSamples)-1].Stopped; stopped > 0 {
+		s += fmt.Sprintf(" | %d stopped", stopped)
+	}
+	return s
+}
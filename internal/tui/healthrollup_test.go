@@ -0,0 +1,50 @@
+package tui
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHealthRollupAndTrend(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	samples := []healthSample{
+		{At: now.Add(-9 * time.Minute), Healthy: 2, Total: 4},
+		{At: now.Add(-7 * time.Minute), Healthy: 2, Total: 4},
+		{At: now.Add(-3 * time.Minute), Healthy: 4, Total: 4},
+		{At: now, Healthy: 3, Total: 4},
+	}
+
+	pct, ok := healthRollup(samples, now.Add(-healthRollupShortWindow), now.Add(time.Nanosecond))
+	if !ok || pct != 87.5 {
+		t.Errorf("expected 87.5%% over the short window, got %v (ok=%v)", pct, ok)
+	}
+	if trend := healthTrend(samples, now); trend != "↑" {
+		t.Errorf("expected improving trend, got %q", trend)
+	}
+	if trend := healthTrend(samples[2:], now); trend != "" {
+		t.Errorf("expected no trend without a previous window, got %q", trend)
+	}
+}
+
+func TestTakeHealthSampleCountsStoppedSeparately(t *testing.T) {
+	now := time.Now()
+	m := model{
+		managementCluster: "alpha",
+		MCHealth:          clusterHealthInfo{ReadyNodes: 3, TotalNodes: 3, LastUpdated: now},
+		readOnly:          true,
+		portForwards: map[string]*portForwardProcess{
+			"Grafana (MC)":    {label: "Grafana (MC)"},
+			"Prometheus (MC)": {label: "Prometheus (MC)"},
+		},
+		portForwardOrder: []string{"Grafana (MC)", "Prometheus (MC)"},
+	}
+	sample := takeHealthSample(m, now)
+	if sample.Healthy != 1 || sample.Total != 1 || sample.Stopped != 2 {
+		t.Fatalf("expected the stopped port-forwards in their own bucket, got %+v", sample)
+	}
+
+	recordHealthSample(&m, now)
+	if got := renderHealthRollup(m); got != "Env health 100% (5m) 100% (1h) | 2 stopped" {
+		t.Errorf("unexpected rollup %q", got)
+	}
+}
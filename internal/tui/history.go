@@ -0,0 +1,132 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+)
+
+// maxStateHistory bounds the number of state transitions kept per port-forward.
+const maxStateHistory = 20
+
+// Port-forward states derived from a portForwardProcess, used for transition history and explanations.
+const (
+	pfStateStarting = "Starting"
+	pfStateRunning  = "Running"
+	pfStateFailed   = "Failed"
+	pfStateStopped  = "Stopped"
+)
+
+// restartCause classifies why a port-forward was restarted.
+type restartCause string
+
+const (
+	restartManual     restartCause = "manual"     // Requested by the user, e.g. with 'r'.
+	restartHealth     restartCause = "health"     // After sleep/wake or a network change broke connections.
+	restartDependency restartCause = "dependency" // Because the cluster it depends on was restarted.
+	restartReconnect  restartCause = "reconnect"  // Automatically after the port-forward failed, see scheduleReconnect.
+)
+
+// restartCauses lists the restart causes in display order.
+var restartCauses = []restartCause{restartManual, restartHealth, restartDependency, restartReconnect}
+
+// stateTransition records a single change of a port-forward's state.
+type stateTransition struct {
+	At     time.Time // When the transition happened.
+	From   string    // Previous state (empty for the first recorded state).
+	To     string    // New state.
+	Reason string    // Status or log message that caused the transition.
+}
+
+// String formats the transition for display, e.g. "10:32:01 Running -> Failed (connection reset)".
+func (t stateTransition) String() string {
+	from := t.From
+	if from == "" {
+		from = "(none)"
+	}
+	s := fmt.Sprintf("%s %s -> %s", t.At.Format("15:04:05"), from, t.To)
+	if t.Reason != "" {
+		s += fmt.Sprintf(" (%s)", t.Reason)
+	}
+	return s
+}
+
+// portForwardState derives the coarse state of a port-forward from its fields.
+func portForwardState(pf *portForwardProcess) string {
+	switch {
+	case pf.forwardingEstablished:
+		return pfStateRunning
+	case pf.err != nil || (!pf.active && pf.lastError != ""):
+		return pfStateFailed
+	case !pf.active:
+		return pfStateStopped
+	default:
+		return pfStateStarting
+	}
+}
+
+// recordStateTransition appends a transition to the port-forward's history if its derived state changed
+// since the last recorded transition. The history is capped at maxStateHistory entries.
+// - pf: The port-forward whose state may have changed.
+// - reason: A short description of what caused the change.
+func recordStateTransition(pf *portForwardProcess, reason string) {
+	newState := portForwardState(pf)
+	var oldState string
+	if n := len(pf.history); n > 0 {
+		oldState = pf.history[n-1].To
+	}
+	if newState == oldState {
+		return
+	}
+	now := time.Now()
+	pf.history = append(pf.history, stateTransition{At: now, From: oldState, To: newState, Reason: reason})
+	if newState == pfStateRunning {
+		pf.runningSince = now
+	} else {
+		pf.runningSince = time.Time{}
+	}
+	if len(pf.history) > maxStateHistory {
+		pf.history = pf.history[len(pf.history)-maxStateHistory:]
+	}
+}
+
+// keepStateHistory carries the state history over to the port-forwards of a new connection that forward
+// the same thing as before: same label and same kube context. Port-forwards of another cluster start with
+// an empty history, even though labels like "Grafana (MC)" repeat across clusters.
+// - previous: The port-forwards before the connection changed.
+// - current: The port-forwards of the new connection.
+func keepStateHistory(previous, current map[string]*portForwardProcess) {
+	for label, pf := range current {
+		if old, ok := previous[label]; ok && old.context == pf.context {
+			pf.history = old.history
+		}
+	}
+}
+
+// lastFailure returns the most recent transition of the port-forward to Failed.
+// Returns false if it has not failed within its history.
+func lastFailure(pf *portForwardProcess) (stateTransition, bool) {
+	for i := len(pf.history) - 1; i >= 0; i-- {
+		if pf.history[i].To == pfStateFailed {
+			return pf.history[i], true
+		}
+	}
+	return stateTransition{}, false
+}
+
+// averageStartupTime returns how long the port-forward took on average to become ready after being
+// (re)started, from the Starting -> Running transitions in its history.
+// Returns false if its history has no such transition.
+func averageStartupTime(pf *portForwardProcess) (time.Duration, bool) {
+	var total time.Duration
+	var n int
+	for i := 1; i < len(pf.history); i++ {
+		if pf.history[i-1].To == pfStateStarting && pf.history[i].To == pfStateRunning {
+			total += pf.history[i].At.Sub(pf.history[i-1].At)
+			n++
+		}
+	}
+	if n == 0 {
+		return 0, false
+	}
+	return total / time.Duration(n), true
+}
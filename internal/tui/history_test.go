@@ -0,0 +1,98 @@
+package tui
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestRecordStateTransition(t *testing.T) {
+	pf := &portForwardProcess{label: "Prometheus (MC)", active: true}
+
+	recordStateTransition(pf, "Initializing...")
+	recordStateTransition(pf, "still initializing") // Same derived state, must not be recorded.
+	if len(pf.history) != 1 {
+		t.Fatalf("expected 1 transition, got %d", len(pf.history))
+	}
+	if got := pf.history[0]; got.From != "" || got.To != pfStateStarting {
+		t.Errorf("unexpected first transition: %+v", got)
+	}
+
+	pf.forwardingEstablished = true
+	recordStateTransition(pf, "Forwarding from 127.0.0.1:8080")
+	if pf.runningSince.IsZero() {
+		t.Error("expected runningSince to be set once running")
+	}
+
+	pf.forwardingEstablished = false
+	pf.active = false
+	pf.err = errors.New("connection reset")
+	recordStateTransition(pf, "Error.")
+
+	want := []string{pfStateStarting, pfStateRunning, pfStateFailed}
+	if len(pf.history) != len(want) {
+		t.Fatalf("expected %d transitions, got %d", len(want), len(pf.history))
+	}
+	for i, state := range want {
+		if pf.history[i].To != state {
+			t.Errorf("transition %d: expected to=%s, got %s", i, state, pf.history[i].To)
+		}
+	}
+	if !pf.runningSince.IsZero() {
+		t.Error("expected runningSince to be cleared once failed")
+	}
+	if pf.history[2].From != pfStateRunning || pf.history[2].Reason != "Error." {
+		t.Errorf("unexpected last transition: %+v", pf.history[2])
+	}
+}
+
+func TestRecordStateTransitionIsBounded(t *testing.T) {
+	pf := &portForwardProcess{label: "Grafana (MC)", active: true}
+	for i := 0; i < maxStateHistory*2; i++ {
+		pf.forwardingEstablished = i%2 == 0
+		recordStateTransition(pf, "flap")
+	}
+	if len(pf.history) != maxStateHistory {
+		t.Fatalf("expected history capped at %d, got %d", maxStateHistory, len(pf.history))
+	}
+}
+
+func TestRestartPortForwardCountsCauses(t *testing.T) {
+	m := model{combinedOutput: newLogBuffer(0, 0)}
+	pf := &portForwardProcess{label: "Grafana (MC)"}
+
+	// A restart that cannot be started is not counted.
+	if cmd := restartPortForward(&m, pf, restartManual, "a", "manual restart"); cmd != nil || pf.restarts[restartManual] != 0 {
+		t.Fatalf("expected a restart without TUI channel to fail uncounted, got %v", pf.restarts)
+	}
+
+	m.TUIChannel = make(chan tea.Msg, 1)
+	restartPortForward(&m, pf, restartManual, "a", "manual restart")
+	restartPortForward(&m, pf, restartDependency, "b", "restart of cluster alpha")
+	restartPortForward(&m, pf, restartManual, "c", "manual restart")
+
+	if pf.restarts[restartManual] != 2 || pf.restarts[restartDependency] != 1 || pf.restarts[restartHealth] != 0 {
+		t.Fatalf("unexpected restart counts: %v", pf.restarts)
+	}
+}
+
+func TestKeepStateHistoryOnlyForTheSameCluster(t *testing.T) {
+	history := []stateTransition{{At: time.Now(), To: pfStateFailed, Reason: "connection reset"}}
+	previous := map[string]*portForwardProcess{
+		"Grafana (MC)":    {label: "Grafana (MC)", context: "teleport.giantswarm.io-alpha", history: history},
+		"Prometheus (MC)": {label: "Prometheus (MC)", context: "teleport.giantswarm.io-alpha", history: history},
+	}
+	current := map[string]*portForwardProcess{
+		"Grafana (MC)":    {label: "Grafana (MC)", context: "teleport.giantswarm.io-alpha"},
+		"Prometheus (MC)": {label: "Prometheus (MC)", context: "teleport.giantswarm.io-beta"},
+	}
+	keepStateHistory(previous, current)
+	if len(current["Grafana (MC)"].history) != 1 {
+		t.Errorf("expected the history of the same cluster's port-forward to be kept")
+	}
+	if len(current["Prometheus (MC)"].history) != 0 {
+		t.Errorf("expected another cluster's port-forward to start with an empty history")
+	}
+}
@@ -0,0 +1,148 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+)
+
+// Actions that a connection switch can perform, as shown in its impact preview.
+const (
+	actionSwitchContext = "Switch context"
+	actionStop          = "Stop"
+	actionRestart       = "Restart"
+	actionStart         = "Start"
+)
+
+// plannedAction is a single step a connection switch will perform.
+type plannedAction struct {
+	Action string // One of the action* constants.
+	Target string // The port-forward label or context affected.
+	Detail string // Additional information, e.g. the port or target context.
+	// Estimate is the expected time until a started or restarted port-forward is ready, from its
+	// earlier starts (see averageStartupTime); zero if unknown.
+	Estimate time.Duration
+}
+
+// String formats the action for display, e.g. "Restart Prometheus (MC) (8080:8080 via teleport.giantswarm.io-mc) ~4s".
+func (a plannedAction) String() string {
+	s := fmt.Sprintf("%s %s", a.Action, a.Target)
+	if a.Detail != "" {
+		s += fmt.Sprintf(" (%s)", a.Detail)
+	}
+	if a.Estimate > 0 {
+		s += " ~" + formatAge(a.Estimate)
+	}
+	return s
+}
+
+// expectedDowntime returns how long the port-forwards of a plan are expected to be unavailable once
+// they are (re)started. They start in parallel, so this is the longest estimate; zero if none is known.
+func expectedDowntime(actions []plannedAction) time.Duration {
+	var longest time.Duration
+	for _, a := range actions {
+		if (a.Action == actionRestart || a.Action == actionStart) && a.Estimate > longest {
+			longest = a.Estimate
+		}
+	}
+	return longest
+}
+
+// planConnectionSwitch computes the impact of switching the TUI to a new MC/WC pair without
+// changing anything: which port-forwards will be stopped, restarted or newly started, and
+// which Kubernetes context will become current. Port-forwards that existed before carry an estimate
+// of their startup time from their history.
+// - m: The current TUI model.
+// - mcName: The target management cluster name.
+// - wcShortName: The target workload cluster short name (optional).
+// Returns the planned actions in the order they will be performed.
+func planConnectionSwitch(m model, mcName, wcShortName string) []plannedAction {
+	target := switchTarget(mcName, wcShortName)
+
+	targetContext := "teleport.giantswarm.io-" + mcName
+	if wcShortName != "" {
+		targetContext = "teleport.giantswarm.io-" + target.getWorkloadClusterContextIdentifier()
+	}
+
+	var actions []plannedAction
+	if targetContext != m.currentKubeContext {
+		actions = append(actions, plannedAction{Action: actionSwitchContext, Target: targetContext, Detail: "from " + m.currentKubeContext})
+	}
+
+	// Port-forwards that are not part of the new set are stopped for good.
+	for _, label := range m.portForwardOrder {
+		pf, ok := m.portForwards[label]
+		if !ok || !(pf.active || pf.stopChan != nil) {
+			continue
+		}
+		if _, kept := target.portForwards[label]; !kept {
+			actions = append(actions, plannedAction{Action: actionStop, Target: label, Detail: pf.port})
+		}
+	}
+
+	// The rest are restarted against the new clusters, or started if new.
+	for _, label := range target.portForwardOrder {
+		newPf, ok := target.portForwards[label]
+		if !ok {
+			continue
+		}
+		action := actionStart
+		var estimate time.Duration
+		if oldPf, existed := m.portForwards[label]; existed {
+			if oldPf.active || oldPf.stopChan != nil {
+				action = actionRestart
+			}
+			estimate, _ = averageStartupTime(oldPf)
+		}
+		actions = append(actions, plannedAction{Action: action, Target: label, Detail: fmt.Sprintf("%s via %s", newPf.port, newPf.context), Estimate: estimate})
+	}
+	return actions
+}
+
+// switchTarget builds the port-forward set of a connection to the given MC/WC pair on a scratch model,
+// so the live one is untouched.
+func switchTarget(mcName, wcShortName string) model {
+	target := model{managementCluster: mcName, workloadCluster: wcShortName}
+	setupPortForwards(&target, mcName, wcShortName)
+	return target
+}
+
+// unsatisfiableAfterSwitch lists the services that cannot work after switching to a new MC/WC pair:
+// everything that transitively depends on a cluster the switch disconnects (see dependencyClosureOf)
+// and that the new connection does not recreate against its own clusters, e.g. the WC port-forwards
+// when switching to an MC only. Ephemeral port-forwards are always listed, as every switch drops them.
+// - m: The current TUI model.
+// - mcName: The target management cluster name.
+// - wcShortName: The target workload cluster short name (optional).
+// Returns one line per service with the cluster it depends on, in display order.
+func unsatisfiableAfterSwitch(m model, mcName, wcShortName string) []string {
+	target := switchTarget(mcName, wcShortName)
+	dropped := map[string]string{} // Panel key of a cluster the switch disconnects -> its name.
+	if m.managementCluster != "" && m.managementCluster != mcName {
+		dropped[mcPaneFocusKey] = m.managementCluster
+	}
+	if wc := m.getWorkloadClusterContextIdentifier(); wc != "" && wc != target.getWorkloadClusterContextIdentifier() {
+		dropped[wcPaneFocusKey] = wc
+	}
+
+	unsatisfiable := map[string]string{}
+	for key, cluster := range dropped {
+		closure, ok := dependencyClosureOf(m, key)
+		if !ok {
+			continue
+		}
+		for _, node := range closure.Downstream {
+			if _, recreated := target.portForwards[node.Key]; !recreated {
+				unsatisfiable[node.Key] = cluster
+			}
+		}
+	}
+	var lines []string
+	for _, key := range dependencyGraphKeys(m) {
+		if cluster, ok := unsatisfiable[key]; ok {
+			lines = append(lines, fmt.Sprintf("%s (depends on cluster %s, which the new connection does not include)", key, cluster))
+		} else if pf, ok := m.portForwards[key]; ok && pf.ephemeral {
+			lines = append(lines, fmt.Sprintf("%s (ephemeral port-forwards are dropped when the connection changes)", key))
+		}
+	}
+	return lines
+}
@@ -0,0 +1,86 @@
+package tui
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPlanConnectionSwitch(t *testing.T) {
+	m := model{managementCluster: "alpha", workloadCluster: "dev", currentKubeContext: "teleport.giantswarm.io-alpha-dev"}
+	setupPortForwards(&m, "alpha", "dev")
+
+	// Switching to an MC only: Alloy moves from the WC to the MC, the MC forwards are restarted.
+	actions := planConnectionSwitch(m, "beta", "")
+
+	want := []plannedAction{
+		{Action: actionSwitchContext, Target: "teleport.giantswarm.io-beta"},
+		{Action: actionStop, Target: "Alloy Metrics (WC)"},
+		{Action: actionRestart, Target: "Prometheus (MC)"},
+		{Action: actionRestart, Target: "Grafana (MC)"},
+		{Action: actionStart, Target: "Alloy Metrics (MC)"},
+	}
+	if len(actions) != len(want) {
+		t.Fatalf("expected %d actions, got %d: %v", len(want), len(actions), actions)
+	}
+	for i, w := range want {
+		if actions[i].Action != w.Action || actions[i].Target != w.Target {
+			t.Errorf("action %d: expected %s %s, got %s", i, w.Action, w.Target, actions[i])
+		}
+	}
+}
+
+func TestPlanConnectionSwitchEstimatesStartup(t *testing.T) {
+	m := model{managementCluster: "alpha", currentKubeContext: "teleport.giantswarm.io-alpha"}
+	setupPortForwards(&m, "alpha", "")
+	start := time.Now().Add(-time.Hour)
+	grafana := m.portForwards["Grafana (MC)"]
+	grafana.history = []stateTransition{
+		{At: start, To: pfStateStarting},
+		{At: start.Add(4 * time.Second), From: pfStateStarting, To: pfStateRunning},
+		{At: start.Add(time.Minute), From: pfStateRunning, To: pfStateStarting},
+		{At: start.Add(time.Minute + 8*time.Second), From: pfStateStarting, To: pfStateRunning},
+	}
+
+	actions := planConnectionSwitch(m, "beta", "")
+	for _, a := range actions {
+		want := time.Duration(0)
+		if a.Target == "Grafana (MC)" {
+			want = 6 * time.Second
+		}
+		if a.Estimate != want {
+			t.Errorf("%s: got estimate %s, want %s", a, a.Estimate, want)
+		}
+	}
+	if got := expectedDowntime(actions); got != 6*time.Second {
+		t.Errorf("expected downtime: got %s, want 6s", got)
+	}
+}
+
+func TestUnsatisfiableAfterSwitch(t *testing.T) {
+	m := model{managementCluster: "alpha", workloadCluster: "dev", currentKubeContext: "teleport.giantswarm.io-alpha-dev"}
+	setupPortForwards(&m, "alpha", "dev")
+	m.portForwards["Ephemeral monitoring/loki (MC)"] = &portForwardProcess{label: "Ephemeral monitoring/loki (MC)", ephemeral: true}
+	m.portForwardOrder = append(m.portForwardOrder, "Ephemeral monitoring/loki (MC)")
+
+	// Switching to another WC of the same MC recreates every standard port-forward; only the
+	// ephemeral one is dropped.
+	if got := unsatisfiableAfterSwitch(m, "alpha", "prod"); len(got) != 1 || got[0] != "Ephemeral monitoring/loki (MC) (ephemeral port-forwards are dropped when the connection changes)" {
+		t.Errorf("expected only the ephemeral port-forward, got %v", got)
+	}
+
+	// Switching to an MC only drops the WC and everything depending on it, and the ephemeral
+	// port-forward on the old MC.
+	got := unsatisfiableAfterSwitch(m, "beta", "")
+	want := []string{
+		"Alloy Metrics (WC) (depends on cluster alpha-dev, which the new connection does not include)",
+		"Ephemeral monitoring/loki (MC) (depends on cluster alpha, which the new connection does not include)",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("got %q, want %q", got[i], want[i])
+		}
+	}
+}
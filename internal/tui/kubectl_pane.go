@@ -0,0 +1,240 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/giantswarm/envctl/internal/utils"
+)
+
+const (
+	// maxKubectlPaneLines bounds the scrollback of the kubectl pane.
+	maxKubectlPaneLines = 1000
+	// maxKubectlHistory bounds the number of remembered kubectl commands.
+	maxKubectlHistory = 50
+)
+
+// kubectlPane holds the state of the embedded kubectl pane.
+// It only runs kubectl (never a shell), scoped to the context of the focused cluster.
+type kubectlPane struct {
+	visible    bool
+	input      textinput.Model
+	viewport   viewport.Model
+	output     []string // Scrollback of commands and their output.
+	history    []string // Previously run commands, oldest first.
+	historyPos int      // Position while browsing history; len(history) means "new command".
+	running    bool     // True while a command is executing.
+}
+
+// kubectlResultMsg carries the output of a command run from the kubectl pane.
+type kubectlResultMsg struct {
+	context string
+	command string
+	output  string
+	err     error
+}
+
+// newKubectlPane creates a hidden kubectl pane.
+func newKubectlPane() kubectlPane {
+	ti := textinput.New()
+	ti.Prompt = "kubectl "
+	ti.Placeholder = "get pods -A"
+	ti.CharLimit = 512
+	return kubectlPane{input: ti, viewport: viewport.New(0, 0)}
+}
+
+// runKubectlCmd runs a kubectl command from the pane asynchronously.
+// - command: The command as typed, reported back with the result.
+// - args: The command split into arguments, see splitShellWords.
+func runKubectlCmd(contextName, command string, args []string) tea.Cmd {
+	return func() tea.Msg {
+		output, err := utils.RunKubectl(contextName, args)
+		return kubectlResultMsg{context: contextName, command: command, output: output, err: err}
+	}
+}
+
+// splitShellWords splits a command line into arguments like a POSIX shell does, without expanding anything:
+// single quotes keep their content literally, double quotes allow backslash escapes of " and \,
+// and a backslash outside quotes escapes the next character. For example
+// `get pods -o jsonpath='{.items[*].metadata.name}'` yields the jsonpath as a single argument.
+// Returns an error for an unterminated quote or a trailing backslash.
+func splitShellWords(line string) ([]string, error) {
+	var (
+		args    []string
+		current strings.Builder
+		inWord  bool
+		quote   rune // The open quote, or 0 outside quotes.
+		escaped bool
+	)
+	for _, r := range line {
+		switch {
+		case escaped:
+			if quote == '"' && r != '"' && r != '\\' {
+				current.WriteRune('\\') // Inside double quotes other backslashes are literal.
+			}
+			current.WriteRune(r)
+			escaped = false
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case quote == '"':
+			switch r {
+			case '"':
+				quote = 0
+			case '\\':
+				escaped = true
+			default:
+				current.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote, inWord = r, true
+		case r == '\\':
+			escaped, inWord = true, true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inWord {
+				args = append(args, current.String())
+				current.Reset()
+				inWord = false
+			}
+		default:
+			current.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if escaped {
+		return nil, fmt.Errorf("trailing backslash")
+	}
+	if inWord {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
+
+// kubectlContextForFocus returns the kube context the kubectl pane is scoped to:
+// the WC if its pane or one of its port-forwards is focused, the MC otherwise.
+func kubectlContextForFocus(m model) string {
+	useWC := m.focusedPanelKey == wcPaneFocusKey
+	if pf, ok := m.portForwards[m.focusedPanelKey]; ok {
+		useWC = pf.isWC
+	}
+	if useWC && m.workloadCluster != "" {
+		return utils.TeleportContextName(m.getWorkloadClusterContextIdentifier())
+	}
+	if m.managementCluster != "" {
+		return utils.TeleportContextName(m.getManagementClusterContextIdentifier())
+	}
+	return m.currentKubeContext
+}
+
+// resizeKubectlPane sizes the pane's scrollback viewport for the current terminal size.
+// The overlay takes 90% x 80% of the screen; the scrollback gets what is left after
+// the title (2 lines), the input, a blank line and the status line.
+func resizeKubectlPane(m *model) {
+	overlayWidth, overlayHeight := kubectlPaneOverlaySize(*m)
+	m.kubectl.viewport.Width = overlayWidth - logOverlayStyle.GetHorizontalFrameSize()
+	m.kubectl.viewport.Height = overlayHeight - logOverlayStyle.GetVerticalFrameSize() - 5
+	if m.kubectl.viewport.Width < 0 {
+		m.kubectl.viewport.Width = 0
+	}
+	if m.kubectl.viewport.Height < 1 {
+		m.kubectl.viewport.Height = 1
+	}
+	m.kubectl.viewport.GotoBottom()
+}
+
+// kubectlPaneOverlaySize returns the outer size of the kubectl pane overlay.
+func kubectlPaneOverlaySize(m model) (width, height int) {
+	return int(float64(m.width) * 0.9), int(float64(m.height) * 0.8)
+}
+
+// appendKubectlOutput adds lines to the pane's scrollback, keeping it bounded, and scrolls to the bottom.
+func appendKubectlOutput(m *model, lines ...string) {
+	m.kubectl.output = append(m.kubectl.output, lines...)
+	if len(m.kubectl.output) > maxKubectlPaneLines {
+		m.kubectl.output = m.kubectl.output[len(m.kubectl.output)-maxKubectlPaneLines:]
+	}
+	m.kubectl.viewport.SetContent(strings.Join(m.kubectl.output, "\n"))
+	m.kubectl.viewport.GotoBottom()
+}
+
+// handleKeyMsgKubectlPane processes key presses while the kubectl pane is open.
+// - Enter: Runs the typed command against the focused cluster's context.
+// - Up/Down: Browse command history.
+// - PgUp/PgDn: Scroll the output.
+// - Esc: Close the pane (its scrollback and history are kept).
+// Other keys edit the command.
+func handleKeyMsgKubectlPane(m model, keyMsg tea.KeyMsg) (model, tea.Cmd) {
+	switch keyMsg.Type {
+	case tea.KeyEsc:
+		m.kubectl.visible = false
+		m.kubectl.input.Blur()
+		return m, nil
+	case tea.KeyEnter:
+		command := strings.TrimSpace(m.kubectl.input.Value())
+		if command == "" || m.kubectl.running {
+			return m, nil
+		}
+		args, err := splitShellWords(command)
+		if err != nil {
+			appendKubectlOutput(&m, fmt.Sprintf("[error] %v: %s", err, command), "")
+			return m, nil
+		}
+		m.kubectl.history = append(m.kubectl.history, command)
+		if len(m.kubectl.history) > maxKubectlHistory {
+			m.kubectl.history = m.kubectl.history[len(m.kubectl.history)-maxKubectlHistory:]
+		}
+		m.kubectl.historyPos = len(m.kubectl.history)
+		m.kubectl.input.Reset()
+		m.kubectl.running = true
+		ctx := kubectlContextForFocus(m)
+		appendKubectlOutput(&m, fmt.Sprintf("$ kubectl --context %s %s", ctx, command))
+		return m, runKubectlCmd(ctx, command, args)
+	case tea.KeyUp:
+		if m.kubectl.historyPos > 0 {
+			m.kubectl.historyPos--
+			m.kubectl.input.SetValue(m.kubectl.history[m.kubectl.historyPos])
+			m.kubectl.input.CursorEnd()
+		}
+		return m, nil
+	case tea.KeyDown:
+		if m.kubectl.historyPos < len(m.kubectl.history)-1 {
+			m.kubectl.historyPos++
+			m.kubectl.input.SetValue(m.kubectl.history[m.kubectl.historyPos])
+			m.kubectl.input.CursorEnd()
+		} else {
+			m.kubectl.historyPos = len(m.kubectl.history)
+			m.kubectl.input.Reset()
+		}
+		return m, nil
+	case tea.KeyPgUp, tea.KeyPgDown:
+		var cmd tea.Cmd
+		m.kubectl.viewport, cmd = m.kubectl.viewport.Update(keyMsg)
+		return m, cmd
+	}
+	var cmd tea.Cmd
+	m.kubectl.input, cmd = m.kubectl.input.Update(keyMsg)
+	return m, cmd
+}
+
+// handleKubectlResultMsg appends the output of a finished kubectl command to the pane.
+func handleKubectlResultMsg(m model, msg kubectlResultMsg) model {
+	m.kubectl.running = false
+	if out := strings.TrimRight(msg.output, "\n"); out != "" {
+		appendKubectlOutput(&m, strings.Split(out, "\n")...)
+	}
+	if msg.err != nil {
+		appendKubectlOutput(&m, fmt.Sprintf("[error] %v", msg.err))
+	}
+	appendKubectlOutput(&m, "")
+	return m
+}
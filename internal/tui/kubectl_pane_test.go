@@ -0,0 +1,73 @@
+package tui
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitShellWords(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    []string
+		wantErr bool
+	}{
+		{input: "get pods -A", want: []string{"get", "pods", "-A"}},
+		{input: "  get   pods  ", want: []string{"get", "pods"}},
+		{input: "get pods -o jsonpath='{.items[*].metadata.name}'", want: []string{"get", "pods", "-o", "jsonpath={.items[*].metadata.name}"}},
+		{input: "get pods -l 'app in (x,y)'", want: []string{"get", "pods", "-l", "app in (x,y)"}},
+		{input: `annotate pod p note="a \"quoted\" value"`, want: []string{"annotate", "pod", "p", `note=a "quoted" value`}},
+		{input: `get pods -o "jsonpath={.a\.b}"`, want: []string{"get", "pods", "-o", `jsonpath={.a\.b}`}},
+		{input: `get configmap my\ map`, want: []string{"get", "configmap", "my map"}},
+		{input: "get pods -l ''", want: []string{"get", "pods", "-l", ""}},
+		{input: "get pods -l 'app=x", wantErr: true},
+		{input: `get pods \`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := splitShellWords(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKubectlContextForFocus(t *testing.T) {
+	m := model{
+		managementCluster:  "alpha",
+		workloadCluster:    "beta",
+		currentKubeContext: "kind-local",
+		portForwards: map[string]*portForwardProcess{
+			"Grafana (MC)":       {label: "Grafana (MC)"},
+			"Alloy Metrics (WC)": {label: "Alloy Metrics (WC)", isWC: true},
+		},
+	}
+	tests := []struct {
+		focus string
+		want  string
+	}{
+		{focus: mcPaneFocusKey, want: "teleport.giantswarm.io-alpha"},
+		{focus: wcPaneFocusKey, want: "teleport.giantswarm.io-alpha-beta"},
+		{focus: "Grafana (MC)", want: "teleport.giantswarm.io-alpha"},
+		{focus: "Alloy Metrics (WC)", want: "teleport.giantswarm.io-alpha-beta"},
+	}
+	for _, tt := range tests {
+		m.focusedPanelKey = tt.focus
+		if got := kubectlContextForFocus(m); got != tt.want {
+			t.Errorf("focus %s: got %s, want %s", tt.focus, got, tt.want)
+		}
+	}
+
+	// Without a workload cluster the WC falls back to the MC; without clusters the current context is used.
+	m.workloadCluster, m.focusedPanelKey = "", wcPaneFocusKey
+	if got := kubectlContextForFocus(m); got != "teleport.giantswarm.io-alpha" {
+		t.Errorf("got %s, want the MC context", got)
+	}
+	m.managementCluster = ""
+	if got := kubectlContextForFocus(m); got != "kind-local" {
+		t.Errorf("got %s, want the current context", got)
+	}
+}
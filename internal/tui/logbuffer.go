@@ -0,0 +1,95 @@
+package tui
+
+import "strings"
+
+// defaultLogBufferBytes caps the memory used by the activity log when Options.LogBufferBytes is not set.
+const defaultLogBufferBytes = 1 << 20 // 1 MiB
+
+// logBuffer is a fixed-capacity ring buffer holding the activity log lines.
+// Appending is O(1): once the buffer is full the oldest line is overwritten instead of the slice being
+// re-sliced and eventually re-allocated. Besides the line capacity, the total size of the stored lines is
+// capped at maxBytes so that a few huge lines (e.g. kubectl or tsh output) cannot exhaust memory.
+// The joined content is rendered lazily and cached until the next append, so viewports that are refreshed
+// on every frame do not re-join an unchanged log.
+// logBuffer is shared by pointer, so copies of the model see the same log.
+type logBuffer struct {
+	lines    []string // Ring storage; the oldest line is at start.
+	start    int      // Index of the oldest line.
+	count    int      // Number of lines currently stored.
+	bytes    int      // Total length of the stored lines.
+	maxBytes int      // Upper bound for bytes; 0 disables the byte cap.
+
+	rendered      string // Cached result of String.
+	renderedValid bool   // True if rendered reflects the current content.
+}
+
+// newLogBuffer creates a log buffer keeping at most maxLines lines and maxBytes bytes of text.
+// Non-positive values fall back to maxCombinedOutputLines and defaultLogBufferBytes.
+func newLogBuffer(maxLines, maxBytes int) *logBuffer {
+	if maxLines <= 0 {
+		maxLines = maxCombinedOutputLines
+	}
+	if maxBytes <= 0 {
+		maxBytes = defaultLogBufferBytes
+	}
+	return &logBuffer{lines: make([]string, maxLines), maxBytes: maxBytes}
+}
+
+// Append adds lines to the end of the log, evicting the oldest lines when the line or byte cap is exceeded.
+// The newest line is always kept, even if it alone exceeds the byte cap.
+func (b *logBuffer) Append(lines ...string) {
+	for _, line := range lines {
+		if b.count == len(b.lines) {
+			b.dropOldest()
+		}
+		b.lines[(b.start+b.count)%len(b.lines)] = line
+		b.count++
+		b.bytes += len(line)
+		for b.bytes > b.maxBytes && b.count > 1 {
+			b.dropOldest()
+		}
+	}
+	if len(lines) > 0 {
+		b.renderedValid = false
+	}
+}
+
+// dropOldest removes the oldest line. The buffer must not be empty.
+func (b *logBuffer) dropOldest() {
+	b.bytes -= len(b.lines[b.start])
+	b.lines[b.start] = "" // Release the string for the garbage collector.
+	b.start = (b.start + 1) % len(b.lines)
+	b.count--
+}
+
+// Len returns the number of lines in the log.
+func (b *logBuffer) Len() int {
+	return b.count
+}
+
+// Lines returns a copy of the log lines, oldest first.
+func (b *logBuffer) Lines() []string {
+	out := make([]string, b.count)
+	for i := range out {
+		out[i] = b.lines[(b.start+i)%len(b.lines)]
+	}
+	return out
+}
+
+// Last returns the newest line, or "" if the log is empty.
+func (b *logBuffer) Last() string {
+	if b.count == 0 {
+		return ""
+	}
+	return b.lines[(b.start+b.count-1)%len(b.lines)]
+}
+
+// String returns the log lines joined by newlines, as set as viewport content.
+// The result is cached until the log changes.
+func (b *logBuffer) String() string {
+	if !b.renderedValid {
+		b.rendered = strings.Join(b.Lines(), "\n")
+		b.renderedValid = true
+	}
+	return b.rendered
+}
@@ -0,0 +1,61 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestLogBufferEvictsOldestLines(t *testing.T) {
+	b := newLogBuffer(3, 0)
+	b.Append("a", "b", "c", "d")
+	b.Append("e")
+	if got := strings.Join(b.Lines(), ","); got != "c,d,e" {
+		t.Fatalf("expected c,d,e, got %s", got)
+	}
+	if b.String() != "c\nd\ne" || b.Last() != "e" {
+		t.Fatalf("unexpected content %q, last %q", b.String(), b.Last())
+	}
+}
+
+func TestLogBufferByteCap(t *testing.T) {
+	b := newLogBuffer(100, 10)
+	b.Append("aaaa", "bbbb", "cccc")
+	if got := strings.Join(b.Lines(), ","); got != "bbbb,cccc" {
+		t.Fatalf("expected bbbb,cccc, got %s", got)
+	}
+	// A single line larger than the cap is still kept.
+	b.Append(strings.Repeat("x", 20))
+	if b.Len() != 1 || b.bytes != 20 {
+		t.Fatalf("expected only the oversized line, got %d lines, %d bytes", b.Len(), b.bytes)
+	}
+}
+
+func TestLogBufferStringCache(t *testing.T) {
+	b := newLogBuffer(10, 0)
+	b.Append("one")
+	if b.String() != "one" {
+		t.Fatalf("unexpected content %q", b.String())
+	}
+	b.Append("two")
+	if b.String() != "one\ntwo" {
+		t.Fatalf("cached content not invalidated: %q", b.String())
+	}
+}
+
+// BenchmarkLogBufferAppend shows that appending to a full buffer costs the same regardless of its capacity.
+func BenchmarkLogBufferAppend(b *testing.B) {
+	for _, capacity := range []int{200, 10000, 1000000} {
+		b.Run(fmt.Sprintf("lines=%d", capacity), func(b *testing.B) {
+			buf := newLogBuffer(capacity, 1<<40)
+			for i := 0; i < capacity; i++ {
+				buf.Append("[SYSTEM] warm-up line")
+			}
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				buf.Append("[Grafana (MC)] Port-forwarding established")
+			}
+		})
+	}
+}
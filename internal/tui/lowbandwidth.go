@@ -0,0 +1,69 @@
+package tui
+
+import (
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// lowBandwidthFrameInterval replaces frameInterval in low-bandwidth mode, so background updates
+// are sent to the terminal at most twice a second.
+const lowBandwidthFrameInterval = 500 * time.Millisecond
+
+// Icons that have an ASCII replacement in low-bandwidth mode.
+var (
+	loggedInIcon  = "●"
+	loggedOutIcon = "○"
+)
+
+// asciiBorder and asciiFocusedBorder replace the box-drawing borders in low-bandwidth mode;
+// every cell is a single byte instead of three.
+var (
+	asciiBorder        = lipgloss.Border{Top: "-", Bottom: "-", Left: "|", Right: "|", TopLeft: "+", TopRight: "+", BottomLeft: "+", BottomRight: "+"}
+	asciiFocusedBorder = lipgloss.Border{Top: "=", Bottom: "=", Left: "#", Right: "#", TopLeft: "#", TopRight: "#", BottomLeft: "#", BottomRight: "#"}
+)
+
+// DetectLowBandwidth reports whether the TUI probably runs over a slow link: inside an SSH session
+// (SSH_CONNECTION, SSH_CLIENT or SSH_TTY is set) or on a terminal with few capabilities (TERM).
+// - getenv: Looks up environment variables, usually os.Getenv.
+func DetectLowBandwidth(getenv func(string) string) bool {
+	for _, name := range []string{"SSH_CONNECTION", "SSH_CLIENT", "SSH_TTY"} {
+		if getenv(name) != "" {
+			return true
+		}
+	}
+	switch term := getenv("TERM"); {
+	case term == "linux", term == "dumb", strings.HasPrefix(term, "vt1"), strings.HasPrefix(term, "vt2"):
+		return true
+	}
+	return false
+}
+
+// applyLowBandwidthStyles switches the package styles to low-bandwidth mode: the 16-color ANSI palette,
+// ASCII borders without border backgrounds, no panel backgrounds and ASCII icons. Together with a
+// longer frame interval this keeps the escape sequences sent per redraw small.
+// It changes package-level styles and must be called before the first render.
+func applyLowBandwidthStyles() {
+	lipgloss.SetColorProfile(termenv.ANSI)
+
+	for _, s := range []*lipgloss.Style{
+		&panelStyle, &helpOverlayStyle, &logOverlayStyle, &newConnectionInputStyle,
+		&panelStatusDefaultStyle, &panelStatusInitializingStyle, &panelStatusAttemptingStyle, &panelStatusRunningStyle,
+		&panelStatusErrorStyle, &panelStatusDegradedStyle, &panelStatusExitedStyle, &logPanelStyle,
+		&contextPaneStyle, &activeContextPaneStyle,
+	} {
+		*s = s.Border(asciiBorder).UnsetBackground().UnsetBorderBackground()
+	}
+	for _, s := range []*lipgloss.Style{
+		&focusedPanelStyle,
+		&focusedPanelStatusDefaultStyle, &focusedPanelStatusInitializingStyle, &focusedPanelStatusAttemptingStyle,
+		&focusedPanelStatusRunningStyle, &focusedPanelStatusErrorStyle, &focusedPanelStatusDegradedStyle, &focusedPanelStatusExitedStyle,
+		&focusedContextPaneStyle, &focusedAndActiveContextPaneStyle,
+	} {
+		*s = s.Border(asciiFocusedBorder).UnsetBackground().UnsetBorderBackground()
+	}
+
+	loggedInIcon, loggedOutIcon = "*", "-"
+}
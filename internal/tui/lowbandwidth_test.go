@@ -0,0 +1,42 @@
+package tui
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDetectLowBandwidth(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want bool
+	}{
+		{name: "local terminal", env: map[string]string{"TERM": "xterm-256color"}, want: false},
+		{name: "ssh session", env: map[string]string{"TERM": "xterm-256color", "SSH_CONNECTION": "10.0.0.1 50000 10.0.0.2 22"}, want: true},
+		{name: "ssh tty", env: map[string]string{"SSH_TTY": "/dev/pts/0"}, want: true},
+		{name: "linux console", env: map[string]string{"TERM": "linux"}, want: true},
+		{name: "vt100", env: map[string]string{"TERM": "vt100"}, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectLowBandwidth(func(k string) string { return tt.env[k] }); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderCacheUsesInterval(t *testing.T) {
+	c := newRenderCache()
+	c.interval = lowBandwidthFrameInterval
+	now := time.Now()
+	c.frame(now, func() string { return "a" })
+	c.invalidate(false)
+
+	if got := c.frame(now.Add(2*frameInterval), func() string { return "b" }); got != "a" {
+		t.Errorf("expected the frame to be throttled for the low-bandwidth interval, got %q", got)
+	}
+	if got := c.frame(now.Add(lowBandwidthFrameInterval), func() string { return "b" }); got != "b" {
+		t.Errorf("expected a redraw after the interval, got %q", got)
+	}
+}
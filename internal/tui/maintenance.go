@@ -0,0 +1,113 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// defaultMaintenanceDuration is how long a maintenance window lasts when Options.MaintenanceDuration is not set.
+const defaultMaintenanceDuration = time.Hour
+
+// Scopes of a maintenance window. Besides these, a port-forward label scopes a window to that port-forward.
+const (
+	maintenanceAll = "all" // Every cluster and port-forward.
+	maintenanceMC  = "MC"  // The management cluster and the port-forwards to it.
+	maintenanceWC  = "WC"  // The workload cluster and the port-forwards to it.
+)
+
+// maintenanceExpiredMsg ends a maintenance window when it expires.
+// until identifies the window, so that the expiry of a window that was already ended or extended is ignored.
+type maintenanceExpiredMsg struct {
+	scope string
+	until time.Time
+}
+
+// inMaintenance reports whether scope is under maintenance at the given time.
+func inMaintenance(m model, scope string, now time.Time) bool {
+	until, ok := m.maintenanceUntil[scope]
+	return ok && now.Before(until)
+}
+
+// portForwardInMaintenance reports whether automatic recovery of pf is suppressed at the given time:
+// maintenance covers everything, the role of the cluster pf targets, or pf itself.
+func portForwardInMaintenance(m model, pf *portForwardProcess, now time.Time) bool {
+	role := maintenanceMC
+	if pf.isWC {
+		role = maintenanceWC
+	}
+	return inMaintenance(m, maintenanceAll, now) || inMaintenance(m, role, now) || inMaintenance(m, pf.label, now)
+}
+
+// focusedMaintenanceScope returns the scope 'm' toggles: the role of a focused cluster pane,
+// or the label of a focused port-forward.
+func focusedMaintenanceScope(m model) string {
+	switch m.focusedPanelKey {
+	case mcPaneFocusKey:
+		return maintenanceMC
+	case wcPaneFocusKey:
+		return maintenanceWC
+	}
+	if _, ok := m.portForwards[m.focusedPanelKey]; ok {
+		return m.focusedPanelKey
+	}
+	return ""
+}
+
+// startMaintenance suspends automatic recovery for scope for the given duration, e.g. during a planned
+// cluster upgrade that would otherwise trigger a storm of reconnects. Health checks and alerts keep running,
+// and manual restarts ('r', 'R') still work. The window ends automatically afterwards.
+func startMaintenance(m model, scope string, d time.Duration) (model, tea.Cmd) {
+	until := time.Now().Add(d)
+	m.maintenanceUntil[scope] = until
+	m.combinedOutput.Append(fmt.Sprintf("[SYSTEM] Maintenance (%s) until %s: no automatic reconnects or restarts.", scope, until.Format("15:04:05")))
+	return m, tea.Tick(d, func(time.Time) tea.Msg { return maintenanceExpiredMsg{scope: scope, until: until} })
+}
+
+// endMaintenance ends the maintenance window of scope. Port-forwards that failed during the window are
+// not reconnected automatically; the log points at 'r' instead, so that recovery stays under the user's control.
+func endMaintenance(m model, scope, reason string) model {
+	delete(m.maintenanceUntil, scope)
+	m.combinedOutput.Append(fmt.Sprintf("[SYSTEM] Maintenance (%s) ended (%s); press 'r' to restart failed port-forwards.", scope, reason))
+	return m
+}
+
+// toggleMaintenance starts a maintenance window for scope with the configured duration, or ends it if one is active.
+func toggleMaintenance(m model, scope string) (model, tea.Cmd) {
+	if scope == "" {
+		m.combinedOutput.Append("[SYSTEM] Cannot start maintenance: focus a cluster pane or port-forward, or use 'M' for everything.")
+		return m, nil
+	}
+	if inMaintenance(m, scope, time.Now()) {
+		return endMaintenance(m, scope, "ended manually"), nil
+	}
+	return startMaintenance(m, scope, m.maintenanceDuration)
+}
+
+// handleMaintenanceExpiredMsg ends the maintenance window identified by msg when it expires.
+func handleMaintenanceExpiredMsg(m model, msg maintenanceExpiredMsg) model {
+	if until, ok := m.maintenanceUntil[msg.scope]; !ok || !until.Equal(msg.until) {
+		return m // The window was ended or replaced in the meantime.
+	}
+	return endMaintenance(m, msg.scope, "window expired")
+}
+
+// renderMaintenance returns the active maintenance windows for the header, e.g. "all until 15:04, Grafana (MC) until 16:00",
+// or an empty string if there are none.
+func renderMaintenance(m model, now time.Time) string {
+	var scopes []string
+	for scope := range m.maintenanceUntil {
+		if inMaintenance(m, scope, now) {
+			scopes = append(scopes, scope)
+		}
+	}
+	sort.Strings(scopes)
+	windows := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		windows = append(windows, fmt.Sprintf("%s until %s", scope, m.maintenanceUntil[scope].Format("15:04")))
+	}
+	return strings.Join(windows, ", ")
+}
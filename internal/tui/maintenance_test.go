@@ -0,0 +1,84 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestMaintenanceSuppressesAutomaticRecovery(t *testing.T) {
+	m := model{
+		combinedOutput:      newLogBuffer(0, 0),
+		portForwards:        map[string]*portForwardProcess{},
+		maintenanceUntil:    map[string]time.Time{},
+		maintenanceDuration: time.Hour,
+		TUIChannel:          make(chan tea.Msg, 1),
+	}
+	mc := &portForwardProcess{label: "Grafana (MC)", active: true, forwardingEstablished: true}
+	wc := &portForwardProcess{label: "Alloy Metrics (WC)", isWC: true, active: true, forwardingEstablished: true}
+	m.portForwards[mc.label], m.portForwards[wc.label] = mc, wc
+	m.portForwardOrder = []string{mc.label, wc.label}
+
+	// Maintenance of the MC role covers the MC port-forwards only.
+	m.focusedPanelKey = mcPaneFocusKey
+	m, _ = toggleMaintenance(m, focusedMaintenanceScope(m))
+	if !portForwardInMaintenance(m, mc, time.Now()) || portForwardInMaintenance(m, wc, time.Now()) {
+		t.Fatal("expected only the MC port-forward to be under maintenance")
+	}
+	if got := renderMaintenance(m, time.Now()); !strings.HasPrefix(got, "MC until ") {
+		t.Fatalf("header = %q", got)
+	}
+
+	// A failure under maintenance is not reconnected; outside of it, it is.
+	m, cmd := handlePortForwardStatusUpdateMsg(m, portForwardStatusUpdateMsg{label: mc.label, status: "Error.", isError: true})
+	if cmd != nil || mc.reconnectPending {
+		t.Fatal("expected no reconnect during maintenance")
+	}
+	m, cmd = handlePortForwardStatusUpdateMsg(m, portForwardStatusUpdateMsg{label: wc.label, status: "Error.", isError: true})
+	if cmd == nil || !wc.reconnectPending {
+		t.Fatal("expected a reconnect outside of maintenance")
+	}
+
+	// A reconnect scheduled before maintenance started is dropped when it fires.
+	m, _ = toggleMaintenance(m, maintenanceAll)
+	m, _ = handlePortForwardReconnectMsg(m, portForwardReconnectMsg{label: wc.label, token: wc.reconnectToken})
+	if wc.restarts[restartReconnect] != 0 {
+		t.Fatal("expected the pending reconnect to be dropped during maintenance")
+	}
+
+	// Sleep/wake still re-checks the clusters but restarts nothing.
+	for _, pf := range []*portForwardProcess{mc, wc} {
+		pf.active, pf.forwardingEstablished, pf.err = true, true, nil
+	}
+	m.managementCluster = "alpha"
+	m.lastWakeCheck = time.Now().Add(-time.Hour)
+	m, _ = handleWakeCheckMsg(m, wakeCheckMsg{at: time.Now()})
+	if wc.restarts[restartHealth] != 0 || mc.restarts[restartHealth] != 0 {
+		t.Fatal("expected no restarts during maintenance")
+	}
+	if !m.MCHealth.IsLoading {
+		t.Fatal("expected the cluster health check to run during maintenance")
+	}
+	if !strings.Contains(m.combinedOutput.Last(), "under maintenance") {
+		t.Fatalf("expected the skipped restarts to be logged, got %q", m.combinedOutput.Last())
+	}
+
+	// The expiry of an older window is ignored; the matching one ends maintenance.
+	until := m.maintenanceUntil[maintenanceAll]
+	m = handleMaintenanceExpiredMsg(m, maintenanceExpiredMsg{scope: maintenanceAll, until: until.Add(-time.Minute)})
+	if !inMaintenance(m, maintenanceAll, time.Now()) {
+		t.Fatal("stale expiry ended maintenance")
+	}
+	m = handleMaintenanceExpiredMsg(m, maintenanceExpiredMsg{scope: maintenanceAll, until: until})
+	if inMaintenance(m, maintenanceAll, time.Now()) || !inMaintenance(m, maintenanceMC, time.Now()) {
+		t.Fatal("expected only the global window to end")
+	}
+
+	// Toggling again ends a window by hand.
+	m, _ = toggleMaintenance(m, maintenanceMC)
+	if len(renderMaintenance(m, time.Now())) != 0 {
+		t.Fatal("expected no maintenance left")
+	}
+}
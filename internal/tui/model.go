@@ -18,14 +18,42 @@ import (
 type newInputStep int
 
 const (
-	mcInputStep newInputStep = iota // Represents the stage where the user inputs the Management Cluster name.
-	wcInputStep                     // Represents the stage where the user inputs the Workload Cluster name.
+	mcInputStep      newInputStep = iota // Represents the stage where the user inputs the Management Cluster name.
+	wcInputStep                          // Represents the stage where the user inputs the Workload Cluster name.
+	confirmInputStep                     // Represents the stage where the user reviews the impact of the switch and confirms it.
 
-	// maxCombinedOutputLines defines the maximum number of lines to keep in the combinedOutput log.
+	// maxCombinedOutputLines defines the default maximum number of lines to keep in the combinedOutput log.
 	// This prevents the log from growing indefinitely and consuming too much memory.
 	maxCombinedOutputLines = 200
 )
 
+// Options holds optional settings that change how the TUI behaves.
+type Options struct {
+	// ReadOnly attaches the TUI without managing anything: no port-forwards are started and
+	// actions that change state (restart, context switch, new connection) are disabled.
+	ReadOnly bool
+	// ReadOnlyReason is shown in the header banner when ReadOnly is set (e.g., who holds the instance lock).
+	ReadOnlyReason string
+	// AutoConfirm skips the impact preview and confirmation step when switching to a new connection.
+	AutoConfirm bool
+	// EnableKubectlPane allows opening the embedded kubectl pane with ':'. It is off by default because
+	// it lets anyone at the keyboard run kubectl against the connected clusters.
+	EnableKubectlPane bool
+	// AlertRules configures when alerts are raised. Use DefaultAlertRules() for the standard thresholds.
+	AlertRules AlertRules
+	// LogBufferLines and LogBufferBytes cap the activity log; the oldest lines are dropped first.
+	// Zero values use the defaults (200 lines, 1 MiB).
+	LogBufferLines int
+	LogBufferBytes int
+	// HealthPauseDuration is how long 'P' pauses health checking. Zero uses the default (30 minutes).
+	HealthPauseDuration time.Duration
+	// MaintenanceDuration is how long a maintenance window ('m', 'M') lasts. Zero uses the default (1 hour).
+	MaintenanceDuration time.Duration
+	// LowBandwidth reduces what is sent to the terminal, e.g. over SSH to a jump host: fewer redraws,
+	// ASCII borders, no backgrounds and 16 colors. See DetectLowBandwidth.
+	LowBandwidth bool
+}
+
 // model represents the state of the TUI application.
 // It holds all the data necessary to render the UI and manage its behavior.
 type model struct {
@@ -43,9 +71,10 @@ type model struct {
 	portForwards     map[string]*portForwardProcess // Map of active port-forwarding processes, keyed by label.
 	portForwardOrder []string                       // Order in which port-forwarding panels (and MC/WC info panes) are displayed and navigated.
 	focusedPanelKey  string                         // Key of the currently focused panel or pane for navigation.
+	pfPage           int                            // Port forward page shown while a cluster pane is focused, see portForwardPageBounds.
 
 	// --- UI State & Output ---
-	combinedOutput    []string       // Log of messages and statuses displayed in the TUI.
+	combinedOutput    *logBuffer     // Log of messages and statuses displayed in the TUI.
 	quitting          bool           // Flag indicating if the application is in the process of quitting.
 	ready             bool           // Flag indicating if the TUI has received initial window size and is ready to render.
 	width             int            // Current width of the terminal window.
@@ -58,11 +87,57 @@ type model struct {
 	mainLogViewport   viewport.Model // Viewport for the main, in-line log panel
 
 	// --- New Connection Input State ---
-	isConnectingNew    bool               // True if the TUI is in 'new connection input' mode.
-	newConnectionInput textinput.Model    // Bubbletea text input component for new cluster names.
-	currentInputStep   newInputStep       // Current step in the new connection input flow (mcInputStep or wcInputStep).
-	stashedMcName      string             // Temporarily stores the MC name while the WC name is being inputted.
-	clusterInfo        *utils.ClusterInfo // Holds fetched cluster list for autocompletion during new connection input.
+	isConnectingNew    bool                   // True if the TUI is in 'new connection input' mode.
+	newConnectionInput textinput.Model        // Bubbletea text input component for new cluster names.
+	currentInputStep   newInputStep           // Current step in the new connection input flow (mcInputStep or wcInputStep).
+	stashedMcName      string                 // Temporarily stores the MC name while the WC name is being inputted.
+	clusterInfo        *utils.ClusterInfo     // Holds fetched cluster list for autocompletion during new connection input.
+	picker             clusterPicker          // State of the cluster picker overlay.
+	loggedInContexts   map[string]bool        // Kubeconfig contexts known to exist, used to show login state in the picker.
+	recentClusters     map[string]time.Time   // Last time each cluster was connected to, shown in the picker.
+	pendingConnection  submitNewConnectionMsg // Connection awaiting confirmation in confirmInputStep.
+	pendingImpact      []plannedAction        // Impact preview of pendingConnection shown for confirmation.
+	unsatisfiable      []string               // Services that cannot work after pendingConnection, see unsatisfiableAfterSwitch.
+	autoConfirm        bool                   // True if new connections are submitted without confirmation.
+
+	// --- Environment Health ---
+	healthSamples       []healthSample // Periodic samples of healthy critical services, used for the rollup and trend in the header.
+	healthPausedUntil   time.Time      // Health checking is paused until this time; zero if it is not paused.
+	healthPauseDuration time.Duration  // How long 'P' pauses health checking.
+
+	// --- Maintenance ---
+	maintenanceUntil    map[string]time.Time // End of each active maintenance window, keyed by scope (see maintenance.go).
+	maintenanceDuration time.Duration        // How long a maintenance window lasts.
+
+	// --- Alerts ---
+	alertRules     AlertRules             // Thresholds for the built-in alert rules.
+	alerts         map[string]activeAlert // Currently firing alerts keyed by rule and subject.
+	unhealthySince map[string]time.Time   // When each cluster was first seen unhealthy, for the cluster rule.
+
+	// --- Kubectl Pane ---
+	kubectlEnabled bool        // True if the kubectl pane may be opened (Options.EnableKubectlPane).
+	kubectl        kubectlPane // State of the embedded kubectl pane.
+
+	// --- Table View ---
+	table pfTable // State of the sortable, filterable port-forward table.
+
+	// --- Restart Tree ---
+	restartTree *restartTreeRun // Restart of a cluster and its port-forwards awaiting confirmation or in progress; nil if none.
+
+	// --- Ephemeral Port-Forwards ---
+	ephemeral ephemeralForm // State of the form creating ephemeral port-forwards.
+
+	// --- Rendering ---
+	render *renderCache // Frame throttling and memoized panels, shared between model copies.
+	events eventStats   // Counters of batched and dropped port-forward status updates, shown in debug mode.
+
+	// --- Sleep/Wake & Network Detection ---
+	lastWakeCheck      time.Time // Wall-clock time of the previous wake check tick.
+	networkFingerprint string    // Network configuration seen at the previous tick.
+
+	// --- Instance Mode ---
+	readOnly       bool   // True if this instance only monitors and must not start or change anything.
+	readOnlyReason string // Explanation shown in the read-only banner.
 
 	// TUIChannel is a channel used by asynchronous operations (e.g., port forwarding, Kubernetes API calls)
 	// to send messages (tea.Msg) back to the TUI's main update loop for processing.
@@ -105,10 +180,10 @@ func (m *model) getWorkloadClusterContextIdentifier() string {
 
 // InitialModel creates the initial state of the TUI model.
 // It takes the management cluster name, workload cluster name (optional),
-// and the initial Kubernetes context as input.
+// the initial Kubernetes context and optional behavior settings as input.
 // It sets up the initial port-forwarding configurations, text input for new connections,
 // and initializes the TUI message channel.
-func InitialModel(mcName, wcName, kubeCtx string) model {
+func InitialModel(mcName, wcName, kubeCtx string, opts Options) model {
 	ti := textinput.New()
 	ti.Placeholder = "Management Cluster"
 	ti.CharLimit = 156 // Arbitrary limit
@@ -117,11 +192,18 @@ func InitialModel(mcName, wcName, kubeCtx string) model {
 	// Create the TUI message channel with a larger buffer
 	tuiMsgChannel := make(chan tea.Msg, 100)
 
+	if opts.LowBandwidth {
+		applyLowBandwidthStyles()
+	}
+
 	// Detect current color profile and set dark mode ON by default
 	colorProfile := lipgloss.ColorProfile().String()
 	lipgloss.SetHasDarkBackground(true) // Force dark mode by default
 	isDarkBg := true                    // Set this explicitly since we're forcing dark mode
 	colorMode := fmt.Sprintf("%s (Dark: %v)", colorProfile, isDarkBg)
+	if opts.LowBandwidth {
+		colorMode += ", low bandwidth"
+	}
 
 	m := model{
 		managementCluster:  mcName,
@@ -129,7 +211,7 @@ func InitialModel(mcName, wcName, kubeCtx string) model {
 		kubeContext:        kubeCtx,
 		portForwards:       make(map[string]*portForwardProcess),
 		portForwardOrder:   make([]string, 0),
-		combinedOutput:     make([]string, 0),
+		combinedOutput:     newLogBuffer(opts.LogBufferLines, opts.LogBufferBytes),
 		MCHealth:           clusterHealthInfo{IsLoading: true},
 		isConnectingNew:    false,
 		newConnectionInput: ti,
@@ -141,12 +223,48 @@ func InitialModel(mcName, wcName, kubeCtx string) model {
 		logOverlayVisible:  false,              // Initialize log overlay as hidden
 		logViewport:        viewport.New(0, 0), // Initialize viewport (size will be set in View)
 		mainLogViewport:    viewport.New(0, 0), // Initialize main log viewport
+		readOnly:           opts.ReadOnly,
+		readOnlyReason:     opts.ReadOnlyReason,
+		autoConfirm:        opts.AutoConfirm,
+		kubectlEnabled:     opts.EnableKubectlPane,
+		alertRules:         opts.AlertRules,
+		alerts:             make(map[string]activeAlert),
+		unhealthySince:     make(map[string]time.Time),
+		maintenanceUntil:   make(map[string]time.Time),
+		kubectl:            newKubectlPane(),
+		ephemeral:          newEphemeralForm(),
+		table:              pfTable{selected: make(map[string]bool)},
+		render:             newRenderCache(),
+	}
+
+	if opts.LowBandwidth {
+		m.render.interval = lowBandwidthFrameInterval
+	}
+
+	m.healthPauseDuration = opts.HealthPauseDuration
+	if m.healthPauseDuration <= 0 {
+		m.healthPauseDuration = defaultHealthPauseDuration
+	}
+	m.maintenanceDuration = opts.MaintenanceDuration
+	if m.maintenanceDuration <= 0 {
+		m.maintenanceDuration = defaultMaintenanceDuration
 	}
 
 	m.logViewport.SetContent("Log overlay initialized...")  // Initial content
 	m.mainLogViewport.SetContent("Main log initialized...") // Initial content for main log
 
 	setupPortForwards(&m, mcName, wcName)
+	// Port-forwards started at launch share one startup operation ID.
+	assignPortForwardCorrelationIDs(&m, newCorrelationID())
+
+	if m.readOnly {
+		// Another instance manages the port-forwards; only show their configuration.
+		for _, pf := range m.portForwards {
+			pf.active = false
+			pf.statusMsg = "Read-only"
+		}
+		m.combinedOutput.Append("[SYSTEM] Read-only mode: " + m.readOnlyReason)
+	}
 
 	if wcName != "" {
 		m.WCHealth = clusterHealthInfo{IsLoading: true}
@@ -164,9 +282,10 @@ func InitialModel(mcName, wcName, kubeCtx string) model {
 // channelReaderCmd creates a tea.Cmd that continuously listens for messages on the provided TUIChannel.
 // When a message is received, it's sent to the Bubbletea update loop for processing.
 // This is crucial for handling asynchronous events and updates within the TUI.
+// Port-forward status updates queued at the same time are delivered as one batch, see readEvent.
 func channelReaderCmd(ch chan tea.Msg) tea.Cmd {
 	return func() tea.Msg {
-		return <-ch
+		return readEvent(ch)
 	}
 }
 
@@ -203,8 +322,11 @@ func (m model) Init() tea.Cmd {
 	}
 
 	// Start port-forwarding processes
-	initialPfCmds := getInitialPortForwardCmds(&m) // Pass model as a pointer
-	cmds = append(cmds, initialPfCmds...)
+	// Port-forwards are owned by the leader instance when running read-only
+	if !m.readOnly {
+		initialPfCmds := getInitialPortForwardCmds(&m) // Pass model as a pointer
+		cmds = append(cmds, initialPfCmds...)
+	}
 
 	// Add a ticker for periodic health updates
 	tickCmd := tea.Tick(healthUpdateInterval, func(t time.Time) tea.Msg {
@@ -212,6 +334,12 @@ func (m model) Init() tea.Cmd {
 	})
 	cmds = append(cmds, tickCmd)
 
+	// Watch for sleep/wake cycles and network changes
+	cmds = append(cmds, wakeCheckCmd())
+
+	// Evaluate alert rules periodically
+	cmds = append(cmds, alertTickCmd())
+
 	// Add channel reader to process messages from TUIChannel
 	cmds = append(cmds, channelReaderCmd(m.TUIChannel))
 
@@ -226,7 +354,22 @@ func (m model) Init() tea.Cmd {
 // After processing a message, it returns the updated model and potentially a new command (tea.Cmd) to be executed.
 // Crucially, after every message processing step, it re-subscribes to the TUIChannel via channelReaderCmd
 // to ensure continuous processing of asynchronous messages.
+// Redraws caused by background messages are throttled to one per frameInterval, see renderCache.
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if _, ok := msg.(frameTickMsg); ok {
+		m.render.handleFrameTick()
+		return m, nil
+	}
+	m.render.invalidate(isInteractiveMsg(msg))
+	newModel, cmd := m.update(msg)
+	if tick := m.render.scheduleFrame(time.Now()); tick != nil {
+		cmd = tea.Batch(cmd, tick)
+	}
+	return newModel, cmd
+}
+
+// update applies a single message to the model; see Update.
+func (m model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmds []tea.Cmd // Holds commands to be batched IF NOT handled by a specific case returning a cmd.
 
 	switch msg := msg.(type) {
@@ -235,6 +378,16 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		var cmd tea.Cmd
 		if m.isConnectingNew && m.newConnectionInput.Focused() {
 			m, cmd = handleKeyMsgInputMode(m, msg)
+		} else if m.kubectl.visible {
+			m, cmd = handleKeyMsgKubectlPane(m, msg)
+		} else if m.picker.visible {
+			m, cmd = handleKeyMsgPicker(m, msg)
+		} else if m.ephemeral.visible {
+			m, cmd = handleKeyMsgEphemeralForm(m, msg)
+		} else if m.table.visible {
+			m, cmd = handleKeyMsgTable(m, msg)
+		} else if m.restartTree != nil && !m.restartTree.started {
+			m, cmd = handleKeyMsgRestartTreeConfirm(m, msg)
 		} else {
 			// Handle special keys for overlay and mode toggling
 			switch msg.String() {
@@ -268,7 +421,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.logOverlayVisible = !m.logOverlayVisible
 				if m.logOverlayVisible {
 					// When opening, set viewport content and move to bottom
-					m.logViewport.SetContent(strings.Join(m.combinedOutput, "\n"))
+					m.logViewport.SetContent(m.combinedOutput.String())
 					m.logViewport.GotoBottom()
 				}
 				return m, channelReaderCmd(m.TUIChannel)
@@ -281,6 +434,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	// Window size messages are handled by a function in handlers.go
 	case tea.WindowSizeMsg:
 		m, cmd := handleWindowSizeMsg(m, msg)
+		resizeKubectlPane(&m)
 		// If log overlay is visible, update its size too
 		if m.logOverlayVisible {
 			// Example: 80% of screen width, 70% of screen height for the log overlay
@@ -306,12 +460,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 				row1Height := lipgloss.Height(renderContextPanesRow(m, contentWidth, maxRow1Height))
 
-				maxRow2Height := int(float64(totalAvailableHeight-headerHeight) * 0.30)
-				if maxRow2Height < 7 {
-					maxRow2Height = 7
-				} else if maxRow2Height > 9 {
-					maxRow2Height = 9
-				}
+				maxRow2Height := portForwardRowMaxHeight(m, contentWidth, totalAvailableHeight-headerHeight)
 				row2Height := lipgloss.Height(renderPortForwardingRow(m, contentWidth, maxRow2Height))
 
 				if m.height >= minHeightForMainLogView {
@@ -340,6 +489,8 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Pass directly to the handler without extra debugging output
 		m, cmd := handlePortForwardStatusUpdateMsg(m, msg)
 		return m, tea.Batch(cmd, channelReaderCmd(m.TUIChannel))
+	case portForwardEventBatchMsg:
+		return handlePortForwardEventBatchMsg(m, msg)
 
 	// New Connection Flow Messages (handlers in connection_flow.go)
 	case submitNewConnectionMsg:
@@ -360,6 +511,35 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// This handler returns (model, tea.Cmd)
 		m, cmd := handleRequestClusterHealthUpdate(m)
 		return m, tea.Batch(cmd, channelReaderCmd(m.TUIChannel))
+	case kubectlResultMsg:
+		m = handleKubectlResultMsg(m, msg)
+		return m, channelReaderCmd(m.TUIChannel)
+	case restartTreeLoginMsg:
+		m, cmd := handleRestartTreeLoginMsg(m, msg)
+		return m, tea.Batch(cmd, channelReaderCmd(m.TUIChannel))
+	case restartTreeTimeoutMsg:
+		m = handleRestartTreeTimeoutMsg(m, msg)
+		return m, channelReaderCmd(m.TUIChannel)
+	case ephemeralExpiredMsg:
+		m = handleEphemeralExpiredMsg(m, msg)
+		return m, channelReaderCmd(m.TUIChannel)
+	case portForwardReconnectMsg:
+		m, cmd := handlePortForwardReconnectMsg(m, msg)
+		return m, tea.Batch(cmd, channelReaderCmd(m.TUIChannel))
+	case healthResumeMsg:
+		m, cmd := handleHealthResumeMsg(m, msg)
+		return m, tea.Batch(cmd, channelReaderCmd(m.TUIChannel))
+	case maintenanceExpiredMsg:
+		m = handleMaintenanceExpiredMsg(m, msg)
+		return m, channelReaderCmd(m.TUIChannel)
+	case alertTickMsg:
+		if !healthChecksPaused(m, time.Time(msg)) {
+			evaluateAlerts(&m, time.Time(msg))
+		}
+		return m, tea.Batch(alertTickCmd(), channelReaderCmd(m.TUIChannel))
+	case wakeCheckMsg:
+		m, cmd := handleWakeCheckMsg(m, msg)
+		return m, tea.Batch(cmd, channelReaderCmd(m.TUIChannel))
 	case kubeContextSwitchedMsg:
 		// This handler returns (model, tea.Cmd)
 		m, cmd := handleKubeContextSwitchedMsg(m, msg)
@@ -377,15 +557,12 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if m.logOverlayVisible {
 			m.logViewport, cmd = m.logViewport.Update(msg)
 			return m, tea.Batch(cmd, channelReaderCmd(m.TUIChannel))
-		} else {
-			// If log overlay is NOT visible, pass mouse events to the main log viewport
-			// (Assuming no other mouse-interactive components are active)
-			m.mainLogViewport, cmd = m.mainLogViewport.Update(msg)
-			return m, tea.Batch(cmd, channelReaderCmd(m.TUIChannel))
 		}
+		// If log overlay is NOT visible, pass mouse events to the main log viewport
+		// (Assuming no other mouse-interactive components are active).
 		// If other mouse-interactive components are added later, handle them here.
-		// For now, if not the log overlay, ignore other mouse events.
-		return m, channelReaderCmd(m.TUIChannel) // Ensure channel reader continues
+		m.mainLogViewport, cmd = m.mainLogViewport.Update(msg)
+		return m, tea.Batch(cmd, channelReaderCmd(m.TUIChannel))
 
 	default:
 		// Handle text input updates if in new connection mode and input is focused,
@@ -400,12 +577,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.logViewport, viewportCmd = m.logViewport.Update(msg)
 			finalCmd = viewportCmd
 		}
-		return m, tea.Batch(finalCmd, channelReaderCmd(m.TUIChannel))
-	}
-
-	// Trim combinedOutput (general operation after message processing)
-	if len(m.combinedOutput) > maxCombinedOutputLines+50 {
-		m.combinedOutput = m.combinedOutput[len(m.combinedOutput)-maxCombinedOutputLines:]
+		cmds = append(cmds, finalCmd)
 	}
 
 	// If the switch statement fell through without returning a specific command,
@@ -421,7 +593,13 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 // Lipgloss is used for styling and layout.
 // If the application is quitting or not yet ready, it displays a status message.
 // If in 'new connection input' mode, it renders the input UI.
+// Unchanged or throttled frames are served from the render cache.
 func (m model) View() string {
+	return m.render.frame(time.Now(), m.view)
+}
+
+// view renders the complete frame; see View.
+func (m model) view() string {
 	if m.quitting {
 		return statusStyle.Render("Cleaning up and quitting...")
 	}
@@ -463,12 +641,7 @@ func (m model) View() string {
 		maxRow1Height = 7
 	}
 
-	maxRow2Height := int(float64(totalAvailableHeight-headerHeight) * 0.30) // Adjusted percentage slightly
-	if maxRow2Height < 7 {
-		maxRow2Height = 7
-	} else if maxRow2Height > 9 {
-		maxRow2Height = 9
-	}
+	maxRow2Height := portForwardRowMaxHeight(m, contentWidth, totalAvailableHeight-headerHeight)
 
 	// ----- ROW 1: MC/WC Info -----
 	row1FinalView := renderContextPanesRow(m, contentWidth, maxRow1Height) // Uses helper from view_helpers.go
@@ -497,7 +670,6 @@ func (m model) View() string {
 			"DEBUG: total=%d fixed=%d log=%d | header=%d row1=%d row2=%d",
 			totalAvailableHeight, heightConsumedByFixedElements, logSectionHeight,
 			headerHeight, row1Height, row2Height)
-		m.combinedOutput = append([]string{debugHeightInfo}, m.combinedOutput...)
 
 		if logSectionHeight < 0 { // Ensure it's not negative if space is very constrained
 			logSectionHeight = 0
@@ -533,7 +705,7 @@ func (m model) View() string {
 		m.mainLogViewport.Height = viewportHeight
 
 		// Set content AFTER setting dimensions
-		m.mainLogViewport.SetContent(strings.Join(m.combinedOutput, "\n"))
+		m.mainLogViewport.SetContent(debugHeightInfo + "\n" + m.combinedOutput.String())
 
 		// Now render log panel with the properly sized viewport
 		combinedLogViewString := renderCombinedLogPanel(&m, contentWidth, logSectionHeight)
@@ -556,7 +728,7 @@ func (m model) View() string {
 			updatedHeaderStr := strings.Replace(currentHeaderView, "h for Help", "h for Help | L for Logs", 1)
 			finalViewLayout[0] = updatedHeaderStr // Update the header in the layout
 		}
-		m.logViewport.SetContent(strings.Join(m.combinedOutput, "\n"))
+		m.logViewport.SetContent(m.combinedOutput.String())
 	}
 
 	// Join all layout elements vertically
@@ -572,8 +744,39 @@ func (m model) View() string {
 		lipgloss.WithWhitespaceBackground(lipgloss.AdaptiveColor{Light: "#FFFFFF", Dark: "#222222"}), // Match the terminal background
 	)
 
-	// ----- OVERLAYS (Help & Log) -----
-	if m.helpVisible {
+	// ----- OVERLAYS (Kubectl, Picker, Help & Log) -----
+	if m.kubectl.visible {
+		overlayWidth, overlayHeight := kubectlPaneOverlaySize(m)
+		kubectlOverlay := renderKubectlPaneOverlay(m, overlayWidth, overlayHeight) // Uses helper from view_helpers.go
+		return lipgloss.Place(
+			m.width, m.height, lipgloss.Center, lipgloss.Center, kubectlOverlay,
+			lipgloss.WithWhitespaceBackground(lipgloss.AdaptiveColor{Light: "rgba(0,0,0,0.1)", Dark: "rgba(0,0,0,0.6)"}),
+		)
+	} else if m.restartTree != nil && !m.restartTree.started {
+		restartOverlay := renderRestartTreeConfirmOverlay(m, m.width) // Uses helper from view_helpers.go
+		return lipgloss.Place(
+			m.width, m.height, lipgloss.Center, lipgloss.Center, restartOverlay,
+			lipgloss.WithWhitespaceBackground(lipgloss.AdaptiveColor{Light: "rgba(0,0,0,0.1)", Dark: "rgba(0,0,0,0.6)"}),
+		)
+	} else if m.ephemeral.visible {
+		ephemeralOverlay := renderEphemeralFormOverlay(m, m.width) // Uses helper from view_helpers.go
+		return lipgloss.Place(
+			m.width, m.height, lipgloss.Center, lipgloss.Center, ephemeralOverlay,
+			lipgloss.WithWhitespaceBackground(lipgloss.AdaptiveColor{Light: "rgba(0,0,0,0.1)", Dark: "rgba(0,0,0,0.6)"}),
+		)
+	} else if m.table.visible {
+		tableOverlay := renderTableOverlay(m, m.width, m.height) // Uses helper from tableview.go
+		return lipgloss.Place(
+			m.width, m.height, lipgloss.Center, lipgloss.Center, tableOverlay,
+			lipgloss.WithWhitespaceBackground(lipgloss.AdaptiveColor{Light: "rgba(0,0,0,0.1)", Dark: "rgba(0,0,0,0.6)"}),
+		)
+	} else if m.picker.visible {
+		pickerOverlay := renderClusterPickerOverlay(m, m.width, m.height) // Uses helper from view_helpers.go
+		return lipgloss.Place(
+			m.width, m.height, lipgloss.Center, lipgloss.Center, pickerOverlay,
+			lipgloss.WithWhitespaceBackground(lipgloss.AdaptiveColor{Light: "rgba(0,0,0,0.1)", Dark: "rgba(0,0,0,0.6)"}),
+		)
+	} else if m.helpVisible {
 		helpOverlay := renderHelpOverlay(m, m.width, m.height) // Uses helper from view_helpers.go
 		return lipgloss.Place(
 			m.width, m.height, lipgloss.Center, lipgloss.Center, helpOverlay,
@@ -0,0 +1,163 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	// pfPanelMinWidth is the narrowest outer width a port forward panel is rendered with before columns are dropped.
+	pfPanelMinWidth = 26
+	// pfMaxColumns is the number of port forward panels shown side by side on wide terminals.
+	pfMaxColumns = 3
+	// pfPanelRowHeight is the height reserved for one row of port forward panels.
+	pfPanelRowHeight = 7
+)
+
+// portForwardPanelKeys returns the labels of the port forward panels in display order.
+func portForwardPanelKeys(m model) []string {
+	var keys []string
+	for _, key := range m.portForwardOrder {
+		if key != mcPaneFocusKey && key != wcPaneFocusKey {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// portForwardGrid returns how many columns and rows of port forward panels fit into the given space.
+// Columns shrink on narrow terminals; rows grow on tall terminals, but never beyond what the panels need.
+// If the panels do not all fit, one line of maxHeight is left for the page indicator.
+func portForwardGrid(numPanels, contentWidth, maxHeight int) (columns, rows int) {
+	columns = contentWidth / pfPanelMinWidth
+	if columns > pfMaxColumns {
+		columns = pfMaxColumns
+	}
+	if columns < 1 {
+		columns = 1
+	}
+	neededRows := (numPanels + columns - 1) / columns
+	if neededRows <= 1 {
+		return columns, 1
+	}
+	rows = maxHeight / pfPanelRowHeight
+	if rows < neededRows {
+		rows = (maxHeight - 1) / pfPanelRowHeight // Make room for the page indicator.
+	}
+	if rows > neededRows {
+		rows = neededRows
+	}
+	if rows < 1 {
+		rows = 1
+	}
+	return columns, rows
+}
+
+// portForwardRowMaxHeight returns the height available to the port forwarding grid.
+// With a single row of panels it keeps the compact fixed height; with more panels it may use up to
+// half of the space below the header so tall terminals show several rows.
+// - m: The current TUI model.
+// - contentWidth: The width of the grid.
+// - availableHeight: The terminal height minus the header.
+func portForwardRowMaxHeight(m model, contentWidth, availableHeight int) int {
+	maxHeight := int(float64(availableHeight) * 0.30)
+	if maxHeight < 7 {
+		maxHeight = 7
+	} else if maxHeight > 9 {
+		maxHeight = 9
+	}
+	numPanels := len(portForwardPanelKeys(m))
+	columns, _ := portForwardGrid(numPanels, contentWidth, maxHeight)
+	if numPanels <= columns {
+		return maxHeight
+	}
+	if maxHeight < pfPanelRowHeight+1 {
+		maxHeight = pfPanelRowHeight + 1 // One row plus the page indicator.
+	}
+	neededRows := (numPanels + columns - 1) / columns
+	tall := availableHeight / 2
+	if needed := neededRows * pfPanelRowHeight; tall > needed {
+		tall = needed
+	}
+	if tall > maxHeight {
+		maxHeight = tall
+	}
+	return maxHeight
+}
+
+// portForwardPageSize returns how many port forward panels are shown at once for the current window size.
+func portForwardPageSize(m model) int {
+	contentWidth := m.width
+	availableHeight := m.height - lipgloss.Height(renderHeader(m, contentWidth))
+	numPanels := len(portForwardPanelKeys(m))
+	columns, rows := portForwardGrid(numPanels, contentWidth, portForwardRowMaxHeight(m, contentWidth, availableHeight))
+	return columns * rows
+}
+
+// portForwardPageBounds returns the half-open index range [first, last) of the panels on the visible page.
+// The page follows the focused panel; while a cluster pane is focused, the page last selected with [ or ] is kept.
+func portForwardPageBounds(m model, pfPanelKeys []string, pageSize int) (first, last int) {
+	if pageSize <= 0 || len(pfPanelKeys) == 0 {
+		return 0, 0
+	}
+	page := m.pfPage
+	for i, key := range pfPanelKeys {
+		if key == m.focusedPanelKey {
+			page = i / pageSize
+			break
+		}
+	}
+	if maxPage := (len(pfPanelKeys) - 1) / pageSize; page > maxPage {
+		page = maxPage
+	}
+	first = page * pageSize
+	last = first + pageSize
+	if last > len(pfPanelKeys) {
+		last = len(pfPanelKeys)
+	}
+	return first, last
+}
+
+// renderPortForwardPageIndicator renders the line below a paged port forwarding grid,
+// e.g. "Port forwards 4-6 of 12 ([ / ] to page) | hidden: 1 failed, 2 degraded".
+func renderPortForwardPageIndicator(m model, pfPanelKeys []string, first, last int) string {
+	indicator := fmt.Sprintf("Port forwards %d-%d of %d ([ / ] to page)", first+1, last, len(pfPanelKeys))
+	counts := make(map[string]int)
+	for i, key := range pfPanelKeys {
+		if i >= first && i < last {
+			continue
+		}
+		if pf, ok := m.portForwards[key]; ok {
+			state, _ := portForwardHealth(m, pf)
+			counts[state]++
+		}
+	}
+	var problems []string
+	for _, state := range []string{pfStateFailed, pfStateDegraded, pfStateStarting} {
+		if counts[state] > 0 {
+			problems = append(problems, fmt.Sprintf("%d %s", counts[state], strings.ToLower(state)))
+		}
+	}
+	if len(problems) > 0 {
+		indicator += " | hidden: " + strings.Join(problems, ", ")
+		return pfPageIndicatorAlertStyle.Render(indicator)
+	}
+	return pfPageIndicatorStyle.Render(indicator)
+}
+
+// pagePortForwards moves the focus to the first panel of the next (delta 1) or previous (delta -1) page,
+// wrapping around at either end.
+func pagePortForwards(m model, delta int) model {
+	pfPanelKeys := portForwardPanelKeys(m)
+	pageSize := portForwardPageSize(m)
+	if len(pfPanelKeys) == 0 || pageSize <= 0 {
+		return m
+	}
+	numPages := (len(pfPanelKeys) + pageSize - 1) / pageSize
+	first, _ := portForwardPageBounds(m, pfPanelKeys, pageSize)
+	m.pfPage = ((first/pageSize+delta)%numPages + numPages) % numPages
+	m.focusedPanelKey = pfPanelKeys[m.pfPage*pageSize]
+	return m
+}
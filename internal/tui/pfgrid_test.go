@@ -0,0 +1,63 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestPortForwardGrid(t *testing.T) {
+	tests := []struct {
+		name                  string
+		panels, width, height int
+		wantColumns, wantRows int
+	}{
+		{name: "three panels fit in one row", panels: 3, width: 120, height: 9, wantColumns: 3, wantRows: 1},
+		{name: "narrow terminal drops columns", panels: 3, width: 60, height: 9, wantColumns: 2, wantRows: 1},
+		{name: "tall terminal shows more rows", panels: 12, width: 120, height: 22, wantColumns: 3, wantRows: 3},
+		{name: "rows are capped by the panels", panels: 5, width: 120, height: 40, wantColumns: 3, wantRows: 2},
+		{name: "short terminal keeps one row", panels: 12, width: 120, height: 8, wantColumns: 3, wantRows: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			columns, rows := portForwardGrid(tt.panels, tt.width, tt.height)
+			if columns != tt.wantColumns || rows != tt.wantRows {
+				t.Errorf("got %dx%d, want %dx%d", columns, rows, tt.wantColumns, tt.wantRows)
+			}
+		})
+	}
+}
+
+func TestPortForwardPagingFollowsFocus(t *testing.T) {
+	m := model{portForwards: make(map[string]*portForwardProcess), width: 100, height: 30}
+	m.portForwardOrder = append(m.portForwardOrder, mcPaneFocusKey)
+	for i := 0; i < 12; i++ {
+		label := fmt.Sprintf("pf-%02d", i)
+		m.portForwardOrder = append(m.portForwardOrder, label)
+		m.portForwards[label] = &portForwardProcess{label: label, active: true, forwardingEstablished: true}
+	}
+	m.portForwards["pf-11"].forwardingEstablished = false
+	m.portForwards["pf-11"].active = false
+	m.portForwards["pf-11"].lastError = "connection refused"
+	m.focusedPanelKey = "pf-04"
+
+	keys := portForwardPanelKeys(m)
+	if first, last := portForwardPageBounds(m, keys, 3); first != 3 || last != 6 {
+		t.Fatalf("expected the page of the focused panel (3-6), got %d-%d", first, last)
+	}
+	indicator := renderPortForwardPageIndicator(m, keys, 3, 6)
+	if !strings.Contains(indicator, "4-6 of 12") || !strings.Contains(indicator, "1 failed") {
+		t.Fatalf("unexpected indicator %q", indicator)
+	}
+
+	pageSize := portForwardPageSize(m)
+	if pageSize >= len(keys) {
+		t.Fatalf("expected the panels not to fit on one page, page size %d", pageSize)
+	}
+	numPages := (len(keys) + pageSize - 1) / pageSize
+	wantPage := (4/pageSize + numPages - 1) % numPages
+	m = pagePortForwards(m, -1)
+	if want := keys[wantPage*pageSize]; m.focusedPanelKey != want || m.pfPage != wantPage {
+		t.Fatalf("expected focus on %q (page %d), got %q (page %d)", want, wantPage, m.focusedPanelKey, m.pfPage)
+	}
+}
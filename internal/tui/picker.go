@@ -0,0 +1,123 @@
+package tui
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// clusterPicker holds the state of the cluster picker overlay.
+type clusterPicker struct {
+	visible bool   // True while the overlay is shown.
+	query   string // Case-insensitive filter typed by the user.
+	cursor  int    // Index of the highlighted entry in the filtered list.
+}
+
+// pickerEntry is a selectable row in the cluster picker: either a management cluster
+// or one of its workload clusters.
+type pickerEntry struct {
+	mc       string    // Management cluster (installation) name.
+	wc       string    // Workload cluster short name; empty for the MC row itself.
+	loggedIn bool      // True if a kubeconfig context exists for the cluster.
+	lastUsed time.Time // When envctl last connected to the cluster; zero if never.
+}
+
+// fullName returns the Teleport cluster name of the entry, e.g. "myinstallation-myworkloadcluster".
+func (e pickerEntry) fullName() string {
+	if e.wc == "" {
+		return e.mc
+	}
+	return e.mc + "-" + e.wc
+}
+
+// pickerEntries lists the clusters known from `tsh kube ls`, grouped by installation (MC first, then its WCs),
+// filtered by the picker query. A WC matches if either its short or full name contains the query.
+func pickerEntries(m model) []pickerEntry {
+	if m.clusterInfo == nil {
+		return nil
+	}
+	query := strings.ToLower(m.picker.query)
+	newEntry := func(mc, wc string) pickerEntry {
+		e := pickerEntry{mc: mc, wc: wc}
+		e.loggedIn = m.loggedInContexts["teleport.giantswarm.io-"+e.fullName()]
+		e.lastUsed = m.recentClusters[e.fullName()]
+		return e
+	}
+
+	mcs := append([]string(nil), m.clusterInfo.ManagementClusters...)
+	sort.Strings(mcs)
+
+	var entries []pickerEntry
+	for _, mc := range mcs {
+		wcs := append([]string(nil), m.clusterInfo.WorkloadClusters[mc]...)
+		sort.Strings(wcs)
+
+		mcMatches := strings.Contains(strings.ToLower(mc), query)
+		var group []pickerEntry
+		for _, wc := range wcs {
+			if mcMatches || strings.Contains(strings.ToLower(mc+"-"+wc), query) {
+				group = append(group, newEntry(mc, wc))
+			}
+		}
+		// Keep the MC row as the group heading whenever any of its clusters match.
+		if mcMatches || len(group) > 0 {
+			entries = append(entries, newEntry(mc, ""))
+			entries = append(entries, group...)
+		}
+	}
+	return entries
+}
+
+// handleKeyMsgPicker processes key presses while the cluster picker overlay is open.
+// - Up/Down (or Ctrl+K/Ctrl+J): Move the highlight.
+// - Enter: Connect to the highlighted cluster. A WC row selects the MC+WC pair, an MC row the MC only.
+// - Esc: Close the picker (clears the filter first if one is set).
+// - Backspace: Remove the last filter character. Other printable keys extend the filter.
+// The connection goes through the same impact preview and confirmation as the 'n' input.
+func handleKeyMsgPicker(m model, keyMsg tea.KeyMsg) (model, tea.Cmd) {
+	entries := pickerEntries(m)
+	switch keyMsg.Type {
+	case tea.KeyEsc:
+		if m.picker.query != "" {
+			m.picker.query = ""
+			m.picker.cursor = 0
+			return m, nil
+		}
+		m.picker = clusterPicker{}
+		return m, nil
+	case tea.KeyUp, tea.KeyCtrlK:
+		if m.picker.cursor > 0 {
+			m.picker.cursor--
+		}
+		return m, nil
+	case tea.KeyDown, tea.KeyCtrlJ:
+		if m.picker.cursor < len(entries)-1 {
+			m.picker.cursor++
+		}
+		return m, nil
+	case tea.KeyBackspace:
+		if m.picker.query != "" {
+			m.picker.query = trimLastRune(m.picker.query)
+			m.picker.cursor = 0
+		}
+		return m, nil
+	case tea.KeyEnter:
+		if m.picker.cursor >= len(entries) {
+			return m, nil
+		}
+		selected := entries[m.picker.cursor]
+		m.picker = clusterPicker{}
+		// Reuse the new connection flow so the switch is previewed and confirmed.
+		m.isConnectingNew = true
+		m.stashedMcName = selected.mc
+		m.newConnectionInput.Focus()
+		return requestNewConnection(m, selected.wc)
+	case tea.KeyRunes:
+		m.picker.query += string(keyMsg.Runes)
+		m.picker.cursor = 0
+		return m, nil
+	}
+	return m, nil
+}
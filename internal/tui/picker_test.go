@@ -0,0 +1,41 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/giantswarm/envctl/internal/utils"
+)
+
+func TestPickerEntriesFilter(t *testing.T) {
+	m := model{
+		clusterInfo: &utils.ClusterInfo{
+			ManagementClusters: []string{"gazelle", "alpaca"},
+			WorkloadClusters: map[string][]string{
+				"alpaca":  {"prod", "dev"},
+				"gazelle": {"operations"},
+			},
+		},
+		loggedInContexts: map[string]bool{"teleport.giantswarm.io-alpaca-dev": true},
+	}
+
+	all := pickerEntries(m)
+	wantAll := []string{"alpaca", "alpaca-dev", "alpaca-prod", "gazelle", "gazelle-operations"}
+	if len(all) != len(wantAll) {
+		t.Fatalf("expected %d entries, got %d", len(wantAll), len(all))
+	}
+	for i, name := range wantAll {
+		if all[i].fullName() != name {
+			t.Errorf("entry %d: expected %s, got %s", i, name, all[i].fullName())
+		}
+	}
+	if !all[1].loggedIn || all[2].loggedIn {
+		t.Errorf("unexpected login state: dev=%v prod=%v", all[1].loggedIn, all[2].loggedIn)
+	}
+
+	// Filtering on a WC keeps its MC as the group heading.
+	m.picker.query = "PROD"
+	filtered := pickerEntries(m)
+	if len(filtered) != 2 || filtered[0].fullName() != "alpaca" || filtered[1].fullName() != "alpaca-prod" {
+		t.Errorf("unexpected filtered entries: %+v", filtered)
+	}
+}
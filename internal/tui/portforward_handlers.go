@@ -3,19 +3,20 @@ package tui
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	// "strings" // Likely not needed anymore with simplified handlers
 
 	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/giantswarm/envctl/internal/utils"
 )
 
 // setupPortForwards initializes or re-initializes the port-forwarding configurations.
 // It clears any existing port forwards and sets up new ones based on the provided
 // management cluster (mcName) and workload cluster (wcName).
-// This function defines the services to be port-forwarded (e.g., Prometheus, Grafana, Alloy Metrics)
-// and their respective configurations.
-//
-// Port-forwarding behavior:
+// The services to be port-forwarded are instantiated from utils.DefaultPortForwardTemplates,
+// the same templates the non-TUI mode uses, according to the role of each cluster:
 // - Prometheus and Grafana are always port-forwarded using the Management Cluster context
 // - Alloy Metrics port-forwarding depends on the cluster configuration:
 //   - If both management and workload clusters are specified, Alloy Metrics points to the Workload Cluster
@@ -33,80 +34,26 @@ func setupPortForwards(m *model, mcName, wcName string) {
 		m.portForwardOrder = append(m.portForwardOrder, wcPaneFocusKey)
 	}
 
-	// Prometheus for MC
+	// Context names are derived from the clusters being set up, which may differ from the model's
+	// current ones during initial setup or while switching connections.
+	clusters := model{managementCluster: mcName, workloadCluster: wcName}
+	var mcContext, wcContext string
 	if mcName != "" {
-		promLabel := "Prometheus (MC)"
-		m.portForwardOrder = append(m.portForwardOrder, promLabel)
-		m.portForwards[promLabel] = &portForwardProcess{
-			label:     promLabel,
-			port:      "8080:8080",
-			isWC:      false,
-			context:   "teleport.giantswarm.io-" + mcName, // mcName is sufficient, no need for m.getManagementClusterContextIdentifier()
-			namespace: "mimir",
-			service:   "service/mimir-query-frontend",
-			active:    true,
-			statusMsg: "Awaiting Setup...",
-		}
-
-		// Grafana for MC
-		grafanaLabel := "Grafana (MC)"
-		m.portForwardOrder = append(m.portForwardOrder, grafanaLabel)
-		m.portForwards[grafanaLabel] = &portForwardProcess{
-			label:     grafanaLabel,
-			port:      "3000:3000",
-			isWC:      false,
-			context:   "teleport.giantswarm.io-" + mcName, // mcName is sufficient
-			namespace: "monitoring",
-			service:   "service/grafana",
-			active:    true,
-			statusMsg: "Awaiting Setup...",
-		}
+		mcContext = "teleport.giantswarm.io-" + clusters.getManagementClusterContextIdentifier()
 	}
-
-	// Configure Alloy Metrics port-forwarding based on cluster selection:
-	// If a Workload Cluster is specified, Alloy Metrics points to the WC
-	// Otherwise, if only a Management Cluster is specified, Alloy Metrics points to the MC
 	if wcName != "" {
-		// Alloy Metrics for WC (when both Management and Workload clusters are provided)
-		alloyLabel := "Alloy Metrics (WC)"
-		m.portForwardOrder = append(m.portForwardOrder, alloyLabel)
-
-		// Construct the correct context name part for WC using the model's helper.
-		// This ensures consistent and correct context identifier generation.
-		// We need to use mcName here as well, because getWorkloadClusterContextIdentifier
-		// might rely on m.managementCluster which is set to mcName.
-		// To avoid issues if m.managementCluster is not yet mcName when this is called
-		// (e.g. during initial setup), we'll temporarily set it.
-		// A cleaner way would be to pass mcName to getWorkloadClusterContextIdentifier
-		// or make getWorkloadClusterContextIdentifier use the passed mcName/wcName.
-		// For now, this direct usage of the model's method after ensuring mcName is correct is acceptable.
-		originalModelMcName := m.managementCluster
-		m.managementCluster = mcName                                   // Temporarily align model's MC name
-		actualWcContextPart := m.getWorkloadClusterContextIdentifier() // Uses the model's method
-		m.managementCluster = originalModelMcName                      // Restore original
-
-		m.portForwards[alloyLabel] = &portForwardProcess{
-			label:     alloyLabel,
-			port:      "12345:12345",
-			isWC:      true,
-			context:   "teleport.giantswarm.io-" + actualWcContextPart,
-			namespace: "kube-system",
-			service:   "service/alloy-metrics-cluster",
-			active:    true,
-			statusMsg: "Awaiting Setup...",
-		}
-	} else if mcName != "" { // Alloy Metrics for MC if no WC is specified
-		// Alloy Metrics for MC (when only a Management Cluster is provided)
-		alloyLabel := "Alloy Metrics (MC)"
-		m.portForwardOrder = append(m.portForwardOrder, alloyLabel)
+		wcContext = "teleport.giantswarm.io-" + clusters.getWorkloadClusterContextIdentifier()
+	}
 
-		m.portForwards[alloyLabel] = &portForwardProcess{
-			label:     alloyLabel,
-			port:      "12345:12345",
-			isWC:      false,                              // Targets MC
-			context:   "teleport.giantswarm.io-" + mcName, // Context for MC
-			namespace: "kube-system",                      // Assuming same namespace
-			service:   "service/alloy-metrics-cluster",    // Assuming same service name
+	for _, spec := range utils.PortForwardsForConnection(mcContext, wcContext, utils.DefaultPortForwardTemplates) {
+		m.portForwardOrder = append(m.portForwardOrder, spec.Label)
+		m.portForwards[spec.Label] = &portForwardProcess{
+			label:     spec.Label,
+			port:      spec.PortSpec(),
+			isWC:      spec.IsWC,
+			context:   spec.Context,
+			namespace: spec.Namespace,
+			service:   spec.Service,
 			active:    true,
 			statusMsg: "Awaiting Setup...",
 		}
@@ -120,41 +67,47 @@ func setupPortForwards(m *model, mcName, wcName string) {
 //   - msg: The portForwardSetupCompletedMsg containing the label of the port-forward,
 //     its initial status, a stop channel (if successful), and any error encountered during setup.
 //
-// Returns the updated model and a nil command as no further async operations are directly initiated here.
+// Returns the updated model and, if the setup failed, the command of an automatic reconnect (see scheduleReconnect).
 func handlePortForwardSetupCompletedMsg(m model, msg portForwardSetupCompletedMsg) (model, tea.Cmd) {
+	var cmd tea.Cmd
 	if pf, ok := m.portForwards[msg.label]; ok {
 		if msg.err != nil { // Error during synchronous setup in StartPortForwardClientGo
 			pf.err = msg.err
 			// msg.status might be empty if error was very early, or could be a partial status.
 			// It's safer to construct a clear error status.
 			pf.statusMsg = fmt.Sprintf("Setup Failed: %v", msg.err)
+			pf.lastError = msg.err.Error()
+			pf.lastErrorAt = time.Now()
 			pf.active = false
 			pf.stopChan = nil
-			m.combinedOutput = append(m.combinedOutput, fmt.Sprintf("[%s ERROR] Port-forward direct setup failed: %v. Async process not started.", msg.label, msg.err))
+			m.combinedOutput.Append(fmt.Sprintf("[%s ERROR] %sPort-forward direct setup failed: %v. Async process not started.", msg.label, correlationTag(pf.correlationID), msg.err))
 		} else {
 			// Synchronous setup in StartPortForwardClientGo was successful.
 			// msg.status contains the initial status log (e.g., "Initializing...").
 			pf.stopChan = msg.stopChan
 			pf.statusMsg = msg.status // Set initial status for TUI display
 			pf.err = nil
+			pf.lastError, pf.lastErrorAt = "", time.Time{} // Recovered; earlier failures stay in the history.
 			pf.active = true
 			// The sendUpdate call within StartPortForwardClientGo also sent this initialStatus for logging.
-			m.combinedOutput = append(m.combinedOutput, fmt.Sprintf("[%s] Port-forward async setup initiated. Initial TUI status: %s", msg.label, msg.status))
+			m.combinedOutput.Append(fmt.Sprintf("[%s] %sPort-forward async setup initiated. Initial TUI status: %s", msg.label, correlationTag(pf.correlationID), msg.status))
 		}
-
-		// Trim combined output
-		if len(m.combinedOutput) > maxCombinedOutputLines {
-			m.combinedOutput = m.combinedOutput[len(m.combinedOutput)-maxCombinedOutputLines:]
+		recordStateTransition(pf, pf.statusMsg)
+		trackRestartTreeOutcome(&m, pf)
+		if msg.err != nil {
+			cmd = scheduleReconnect(&m, pf)
 		}
 	} else {
-		m.combinedOutput = append(m.combinedOutput, fmt.Sprintf("[TUI WARNING] No Port-forward found for label['%s'] during SetupCompleted.", msg.label))
+		// The port-forward was removed while it was being set up, e.g. an ephemeral one removed with 'd'.
+		// Stop the listener that was started for it, otherwise it would keep holding the local port.
+		if msg.err == nil && msg.stopChan != nil {
+			close(msg.stopChan)
+		}
+		m.combinedOutput.Append(fmt.Sprintf("[TUI WARNING] No Port-forward found for label['%s'] during SetupCompleted; stopped it.", msg.label))
 	}
 
 	// Trim combined output - typically done at end of model.Update
-	if len(m.combinedOutput) > maxCombinedOutputLines+100 {
-		m.combinedOutput = m.combinedOutput[len(m.combinedOutput)-maxCombinedOutputLines:]
-	}
-	return m, nil
+	return m, cmd
 }
 
 // handlePortForwardStatusUpdateMsg processes asynchronous status updates received from an active port-forwarding process.
@@ -163,17 +116,20 @@ func handlePortForwardSetupCompletedMsg(m model, msg portForwardSetupCompletedMs
 // It updates the specific port-forward's state in the model and appends relevant information to the combined activity log.
 // - m: The current TUI model.
 // - msg: The portForwardStatusUpdateMsg containing the label, status text, log output, and flags indicating readiness or error.
-// Returns the updated model and a nil command.
+// Returns the updated model and, if the port-forward failed, the command of an automatic reconnect (see scheduleReconnect).
 func handlePortForwardStatusUpdateMsg(m model, msg portForwardStatusUpdateMsg) (model, tea.Cmd) {
+	var cmd tea.Cmd
 	if pf, ok := m.portForwards[msg.label]; ok {
+		tag := correlationTag(pf.correlationID)
+
 		// If status is provided, update the port-forward's status message
 		if msg.status != "" {
 			pf.statusMsg = msg.status
 			// Only log status changes to the activity log if they're meaningful
 			if !strings.HasPrefix(msg.status, "Initializing") &&
 				!strings.Contains(msg.status, "Forwarding from") {
-				m.combinedOutput = append(m.combinedOutput,
-					fmt.Sprintf("[%s] Status changed: %s", msg.label, msg.status))
+				m.combinedOutput.Append(
+					fmt.Sprintf("[%s] %sStatus changed: %s", msg.label, tag, msg.status))
 			}
 		}
 
@@ -183,41 +139,53 @@ func handlePortForwardStatusUpdateMsg(m model, msg portForwardStatusUpdateMsg) (
 			// pf.output = append(pf.output, msg.outputLog) // REMOVED: This line sent logs to individual panel
 
 			// Format for the combined log with a prefix
-			logEntry := fmt.Sprintf("[%s] %s", msg.label, msg.outputLog)
-			m.combinedOutput = append(m.combinedOutput, logEntry)
+			logEntry := fmt.Sprintf("[%s] %s%s", msg.label, tag, msg.outputLog)
+			m.combinedOutput.Append(logEntry)
 		}
 
 		// Update port-forward state based on message flags
 		if msg.isError {
 			pf.active = false
 			pf.forwardingEstablished = false
+			pf.lastErrorAt = time.Now()
+			pf.lastError = msg.outputLog
+			if pf.lastError == "" {
+				pf.lastError = msg.status
+			}
 
 			// Add an error notification if there was no outputLog
 			if msg.outputLog == "" && msg.status == "" {
-				m.combinedOutput = append(m.combinedOutput,
-					fmt.Sprintf("[%s] Error occurred (no details provided)", msg.label))
+				m.combinedOutput.Append(
+					fmt.Sprintf("[%s] %sError occurred (no details provided)", msg.label, tag))
 			}
 		} else if msg.isReady {
 			pf.forwardingEstablished = true
 			pf.active = true
+			pf.reconnectAttempts = 0
+			pf.lastError, pf.lastErrorAt = "", time.Time{}
 
 			// Add a ready notification if there was no status message
 			if msg.status == "" {
-				m.combinedOutput = append(m.combinedOutput,
-					fmt.Sprintf("[%s] Port-forwarding established", msg.label))
+				m.combinedOutput.Append(
+					fmt.Sprintf("[%s] %sPort-forwarding established", msg.label, tag))
 			}
 		}
+
+		reason := msg.status
+		if reason == "" {
+			reason = msg.outputLog
+		}
+		recordStateTransition(pf, reason)
+		trackRestartTreeOutcome(&m, pf)
+		if msg.isError {
+			cmd = scheduleReconnect(&m, pf)
+		}
 	} else {
 		// Only add this warning if the port-forward doesn't exist
-		m.combinedOutput = append(m.combinedOutput,
+		m.combinedOutput.Append(
 			fmt.Sprintf("[TUI WARNING] No Port-forward found for label['%s']", msg.label))
 	}
 
-	// Trim combined output to prevent excessive growth
-	if len(m.combinedOutput) > maxCombinedOutputLines+100 {
-		m.combinedOutput = m.combinedOutput[len(m.combinedOutput)-maxCombinedOutputLines:]
-	}
-
 	// Trim port-forward's output if it exists
 	if pf, ok := m.portForwards[msg.label]; ok {
 		if len(pf.output) > maxCombinedOutputLines {
@@ -225,7 +193,7 @@ func handlePortForwardStatusUpdateMsg(m model, msg portForwardStatusUpdateMsg) (
 		}
 	}
 
-	return m, nil
+	return m, cmd
 }
 
 // getInitialPortForwardCmds generates a slice of tea.Cmds to initiate all active port-forwarding processes
@@ -250,7 +218,7 @@ func getInitialPortForwardCmds(m *model) []tea.Cmd {
 					p.statusMsg = "Error: TUIChannel nil"
 					p.active = false
 				}
-				m.combinedOutput = append(m.combinedOutput, fmt.Sprintf("[CRITICAL ERROR] TUIChannel is nil for %s. PF not started.", label))
+				m.combinedOutput.Append(fmt.Sprintf("[CRITICAL ERROR] TUIChannel is nil for %s. PF not started.", label))
 				continue
 			}
 			pfCmds = append(pfCmds, startPortForwardCmd(pf.label, pf.context, pf.namespace, pf.service, pf.port, m.TUIChannel))
@@ -258,3 +226,70 @@ func getInitialPortForwardCmds(m *model) []tea.Cmd {
 	}
 	return pfCmds
 }
+
+// stopPortForward stops a port-forward and keeps it stopped until it is restarted.
+// - reason: Recorded in the port-forward's state history.
+func stopPortForward(m *model, pf *portForwardProcess, reason string) {
+	cancelReconnect(pf)
+	if pf.stopChan != nil {
+		close(pf.stopChan)
+		pf.stopChan = nil
+	}
+	pf.active = false
+	pf.forwardingEstablished = false
+	pf.err = nil
+	pf.lastError, pf.lastErrorAt = "", time.Time{}
+	pf.statusMsg = "Stopped"
+	recordStateTransition(pf, reason)
+	m.combinedOutput.Append(fmt.Sprintf("[%s] %sStopped (%s).", pf.label, correlationTag(pf.correlationID), reason))
+}
+
+// restartPortForward stops a port-forward (if running) and returns the command that starts it again.
+// The UI is updated immediately to show that a restart is in progress.
+// - m: The TUI model, used for logging and the TUI channel.
+// - pf: The port-forward to restart.
+// - cause: Why the restart was triggered, counted per port-forward (see explainPortForward).
+// - correlationID: The operation the restart belongs to; its log lines are tagged with it.
+// - reason: Why the restart happens, recorded in the state history (e.g. "manual restart").
+// Returns the start command, or nil if the port-forward cannot be started.
+func restartPortForward(m *model, pf *portForwardProcess, cause restartCause, correlationID, reason string) tea.Cmd {
+	pf.correlationID = correlationID
+	cancelReconnect(pf)
+	if cause != restartReconnect {
+		pf.reconnectAttempts = 0
+	}
+	tag := correlationTag(pf.correlationID)
+
+	// Stop the existing port-forward if it's running
+	if pf.stopChan != nil {
+		m.combinedOutput.Append(fmt.Sprintf("[%s] %sSending stop signal...", pf.label, tag))
+		close(pf.stopChan)
+		pf.stopChan = nil
+	}
+
+	// Update UI immediately to reflect that a restart is in progress
+	pf.statusMsg = "Restarting..."
+	pf.output = []string{} // Clear old specific output for this PF
+	pf.err = nil
+	pf.lastError, pf.lastErrorAt = "", time.Time{}
+	pf.active = true // It is attempting to become active
+	pf.forwardingEstablished = false
+	recordStateTransition(pf, reason)
+
+	m.combinedOutput.Append(fmt.Sprintf("[%s] %sAttempting restart...", pf.label, tag))
+
+	// Start the new port-forward using startPortForwardCmd
+	if m.TUIChannel == nil {
+		m.combinedOutput.Append(fmt.Sprintf("[%s ERROR] TUIChannel is nil. Cannot restart.", pf.label))
+		pf.statusMsg = "Restart Failed (Internal Error)"
+		pf.lastError, pf.lastErrorAt = "TUIChannel is nil", time.Now()
+		pf.active = false
+		return nil
+	}
+	// Only restarts that are actually started count, see explainPortForward.
+	if pf.restarts == nil {
+		pf.restarts = make(map[restartCause]int)
+	}
+	pf.restarts[cause]++
+	return startPortForwardCmd(pf.label, pf.context, pf.namespace, pf.service, pf.port, m.TUIChannel)
+}
@@ -0,0 +1,28 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestReadOnlyRefusesMutatingKeys(t *testing.T) {
+	m := model{
+		readOnly:        true,
+		readOnlyReason:  "started with --read-only",
+		kubectlEnabled:  true,
+		kubectl:         newKubectlPane(),
+		combinedOutput:  newLogBuffer(0, 0),
+		focusedPanelKey: mcPaneFocusKey,
+	}
+	for _, key := range []string{"n", "r", "R", "s", ":"} {
+		m, _ = handleKeyMsgGlobal(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}, nil)
+		if !strings.Contains(m.combinedOutput.Last(), "Read-only mode: '"+key+"' is disabled") {
+			t.Errorf("key %q was not refused, last log line: %q", key, m.combinedOutput.Last())
+		}
+	}
+	if m.kubectl.visible || m.isConnectingNew {
+		t.Fatal("read-only mode opened a mutating view")
+	}
+}
@@ -0,0 +1,96 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	// reconnectBaseDelay is the delay before the first automatic reconnect of a failed port-forward;
+	// it doubles with every further attempt up to reconnectMaxDelay.
+	reconnectBaseDelay = 2 * time.Second
+	// reconnectMaxDelay caps the delay between automatic reconnects.
+	reconnectMaxDelay = time.Minute
+	// maxReconnectAttempts is the number of automatic reconnects after which envctl gives up until
+	// the port-forward is restarted by hand or becomes ready again.
+	maxReconnectAttempts = 5
+)
+
+// portForwardReconnectMsg triggers a scheduled automatic reconnect of a failed port-forward.
+type portForwardReconnectMsg struct {
+	label string
+	token int // Must match the port-forward's reconnectToken, otherwise the reconnect was cancelled.
+}
+
+// reconnectDelay returns the backoff before the given reconnect attempt (0 for the first).
+func reconnectDelay(attempt int) time.Duration {
+	delay := reconnectBaseDelay
+	for i := 0; i < attempt && delay < reconnectMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > reconnectMaxDelay {
+		delay = reconnectMaxDelay
+	}
+	return delay
+}
+
+// scheduleReconnect schedules an automatic reconnect of a port-forward that failed, with exponential
+// backoff. Nothing is scheduled if a reconnect is already pending, while health checks are paused or
+// the port-forward is under maintenance, or once maxReconnectAttempts is reached.
+// Returns the command delivering the portForwardReconnectMsg, or nil.
+func scheduleReconnect(m *model, pf *portForwardProcess) tea.Cmd {
+	if pf.reconnectPending || m.readOnly || portForwardState(pf) != pfStateFailed {
+		return nil
+	}
+	if healthChecksPaused(*m, time.Now()) {
+		m.combinedOutput.Append(fmt.Sprintf("[%s] Not reconnecting while health checks are paused; press 'r' to restart.", pf.label))
+		return nil
+	}
+	if portForwardInMaintenance(*m, pf, time.Now()) {
+		m.combinedOutput.Append(fmt.Sprintf("[%s] Not reconnecting during maintenance; press 'r' to restart.", pf.label))
+		return nil
+	}
+	if pf.reconnectAttempts >= maxReconnectAttempts {
+		m.combinedOutput.Append(fmt.Sprintf("[%s] Giving up after %d reconnect attempts; press 'r' to restart.", pf.label, pf.reconnectAttempts))
+		return nil
+	}
+	delay := reconnectDelay(pf.reconnectAttempts)
+	pf.reconnectPending = true
+	pf.reconnectToken++
+	m.combinedOutput.Append(fmt.Sprintf("[%s] Reconnecting in %s (attempt %d/%d)...", pf.label, delay, pf.reconnectAttempts+1, maxReconnectAttempts))
+	label, token := pf.label, pf.reconnectToken
+	return tea.Tick(delay, func(time.Time) tea.Msg { return portForwardReconnectMsg{label: label, token: token} })
+}
+
+// cancelReconnect cancels a pending automatic reconnect, e.g. because the port-forward was restarted or stopped.
+func cancelReconnect(pf *portForwardProcess) {
+	pf.reconnectPending = false
+	pf.reconnectToken++
+}
+
+// handlePortForwardReconnectMsg restarts the port-forward if the reconnect is still wanted: it was not
+// cancelled, the port-forward is still failed, and neither health checks were paused nor maintenance
+// started in the meantime.
+func handlePortForwardReconnectMsg(m model, msg portForwardReconnectMsg) (model, tea.Cmd) {
+	pf, ok := m.portForwards[msg.label]
+	if !ok || !pf.reconnectPending || pf.reconnectToken != msg.token {
+		return m, nil
+	}
+	pf.reconnectPending = false
+	if portForwardState(pf) != pfStateFailed {
+		return m, nil
+	}
+	if healthChecksPaused(m, time.Now()) {
+		m.combinedOutput.Append(fmt.Sprintf("[%s] Not reconnecting while health checks are paused; press 'r' to restart.", pf.label))
+		return m, nil
+	}
+	if portForwardInMaintenance(m, pf, time.Now()) {
+		m.combinedOutput.Append(fmt.Sprintf("[%s] Not reconnecting during maintenance; press 'r' to restart.", pf.label))
+		return m, nil
+	}
+	pf.reconnectAttempts++
+	reason := fmt.Sprintf("automatic reconnect %d/%d", pf.reconnectAttempts, maxReconnectAttempts)
+	return m, restartPortForward(&m, pf, restartReconnect, newCorrelationID(), reason)
+}
@@ -0,0 +1,87 @@
+package tui
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestReconnectAfterFailure(t *testing.T) {
+	m := model{combinedOutput: newLogBuffer(0, 0), portForwards: map[string]*portForwardProcess{}, TUIChannel: make(chan tea.Msg, 1)}
+	pf := &portForwardProcess{label: "Grafana (MC)", active: true, forwardingEstablished: true}
+	m.portForwards[pf.label] = pf
+
+	m, cmd := handlePortForwardStatusUpdateMsg(m, portForwardStatusUpdateMsg{label: pf.label, status: "Error.", outputLog: "Forwarding failed: EOF", isError: true})
+	if cmd == nil || !pf.reconnectPending {
+		t.Fatal("expected a reconnect to be scheduled")
+	}
+	token := pf.reconnectToken
+
+	// Further errors of the same failure do not schedule another reconnect.
+	m, cmd = handlePortForwardStatusUpdateMsg(m, portForwardStatusUpdateMsg{label: pf.label, status: "Error.", isError: true})
+	if cmd != nil || pf.reconnectToken != token {
+		t.Fatal("expected no second reconnect")
+	}
+
+	// A stale reconnect is ignored.
+	if _, cmd := handlePortForwardReconnectMsg(m, portForwardReconnectMsg{label: pf.label, token: token - 1}); cmd != nil || pf.restarts[restartReconnect] != 0 {
+		t.Fatal("expected a stale reconnect to be ignored")
+	}
+
+	m, _ = handlePortForwardReconnectMsg(m, portForwardReconnectMsg{label: pf.label, token: token})
+	if pf.restarts[restartReconnect] != 1 || pf.reconnectAttempts != 1 || pf.reconnectPending {
+		t.Fatalf("expected one reconnect, got restarts %v, attempts %d", pf.restarts, pf.reconnectAttempts)
+	}
+
+	// Becoming ready resets the backoff.
+	m, _ = handlePortForwardStatusUpdateMsg(m, portForwardStatusUpdateMsg{label: pf.label, status: "Forwarding from 127.0.0.1:3000", isReady: true})
+	if pf.reconnectAttempts != 0 {
+		t.Fatalf("expected attempts reset, got %d", pf.reconnectAttempts)
+	}
+
+	// Stopping by hand cancels a pending reconnect.
+	_, _ = handlePortForwardStatusUpdateMsg(m, portForwardStatusUpdateMsg{label: pf.label, status: "Error.", isError: true})
+	token = pf.reconnectToken
+	stopPortForward(&m, pf, "stopped by user")
+	if _, cmd := handlePortForwardReconnectMsg(m, portForwardReconnectMsg{label: pf.label, token: token}); cmd != nil || pf.restarts[restartReconnect] != 1 {
+		t.Fatal("expected the reconnect to be cancelled")
+	}
+}
+
+func TestReconnectCancelledByNewConnection(t *testing.T) {
+	m := model{combinedOutput: newLogBuffer(0, 0), portForwards: map[string]*portForwardProcess{}, TUIChannel: make(chan tea.Msg, 1)}
+	pf := &portForwardProcess{label: "Grafana (MC)", active: true, forwardingEstablished: true}
+	m.portForwards[pf.label] = pf
+
+	m, cmd := handlePortForwardStatusUpdateMsg(m, portForwardStatusUpdateMsg{label: pf.label, status: "Error.", outputLog: "Forwarding failed: EOF", isError: true})
+	if cmd == nil || !pf.reconnectPending {
+		t.Fatal("expected a reconnect to be scheduled")
+	}
+	token := pf.reconnectToken
+
+	m, _ = handleSubmitNewConnectionMsg(m, submitNewConnectionMsg{mc: "beta"}, nil)
+	if pf.reconnectPending {
+		t.Fatal("expected the new connection to cancel the pending reconnect")
+	}
+	if _, cmd := handlePortForwardReconnectMsg(m, portForwardReconnectMsg{label: pf.label, token: token}); cmd != nil || pf.restarts[restartReconnect] != 0 || pf.stopChan != nil {
+		t.Fatal("expected the stale reconnect to be ignored after a new connection")
+	}
+}
+
+func TestReconnectGivesUp(t *testing.T) {
+	m := model{combinedOutput: newLogBuffer(0, 0)}
+	pf := &portForwardProcess{label: "Grafana (MC)", lastError: "boom", reconnectAttempts: maxReconnectAttempts}
+	if cmd := scheduleReconnect(&m, pf); cmd != nil {
+		t.Fatal("expected no reconnect after the maximum number of attempts")
+	}
+}
+
+func TestReconnectDelay(t *testing.T) {
+	want := []time.Duration{2 * time.Second, 4 * time.Second, 8 * time.Second, 16 * time.Second, 32 * time.Second, time.Minute, time.Minute}
+	for attempt, w := range want {
+		if got := reconnectDelay(attempt); got != w {
+			t.Errorf("attempt %d: got %s, want %s", attempt, got, w)
+		}
+	}
+}
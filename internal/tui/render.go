@@ -0,0 +1,130 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// frameInterval is the minimum time between two full redraws caused by background updates
+// (log lines, port-forward status, health checks). Keyboard, mouse and resize events redraw immediately.
+const frameInterval = 50 * time.Millisecond
+
+// frameTickMsg triggers the redraw that was held back by frame throttling.
+type frameTickMsg struct{}
+
+// renderCache throttles full redraws and memoizes panels whose inputs did not change.
+// Busy environments can produce dozens of messages per second; without throttling every log line
+// would re-render the complete layout. The cache is shared by pointer, so the value copies of the
+// model passed to View see the same state. A nil cache disables caching.
+type renderCache struct {
+	view        string        // Last rendered frame.
+	renderedAt  time.Time     // When view was rendered.
+	dirty       bool          // True if the model may have changed since view was rendered.
+	immediate   bool          // True if the next View must redraw even within frameInterval.
+	tickPending bool          // True if a frameTickMsg is scheduled.
+	interval    time.Duration // Minimum time between redraws caused by background updates.
+
+	panels map[string]cachedPanel // Memoized panels keyed by panel ID.
+}
+
+// cachedPanel is a rendered panel together with the key describing its inputs.
+type cachedPanel struct {
+	key  string
+	view string
+}
+
+// newRenderCache creates an empty render cache redrawing at most once per frameInterval.
+func newRenderCache() *renderCache {
+	return &renderCache{panels: make(map[string]cachedPanel), interval: frameInterval}
+}
+
+// isInteractiveMsg reports whether msg comes from the user or the terminal and must be reflected without delay.
+func isInteractiveMsg(msg tea.Msg) bool {
+	switch msg.(type) {
+	case tea.KeyMsg, tea.MouseMsg, tea.WindowSizeMsg:
+		return true
+	}
+	return false
+}
+
+// invalidate marks the current frame as outdated.
+// - immediate: Redraw on the next View instead of waiting for frameInterval to pass.
+func (c *renderCache) invalidate(immediate bool) {
+	if c == nil {
+		return
+	}
+	c.dirty = true
+	c.immediate = c.immediate || immediate
+}
+
+// handleFrameTick lets the next View redraw the frame that was held back.
+func (c *renderCache) handleFrameTick() {
+	if c == nil {
+		return
+	}
+	c.tickPending = false
+	c.immediate = true
+}
+
+// scheduleFrame returns a command that delivers a frameTickMsg once the current frame interval is over,
+// if the frame is outdated and would otherwise not be redrawn. Returns nil if no tick is needed.
+func (c *renderCache) scheduleFrame(now time.Time) tea.Cmd {
+	if c == nil || !c.dirty || c.immediate || c.tickPending {
+		return nil
+	}
+	wait := c.interval - now.Sub(c.renderedAt)
+	if wait <= 0 {
+		return nil
+	}
+	c.tickPending = true
+	return tea.Tick(wait, func(time.Time) tea.Msg { return frameTickMsg{} })
+}
+
+// frame returns the cached frame while it is current or throttled, and calls render otherwise.
+func (c *renderCache) frame(now time.Time, render func() string) string {
+	if c == nil {
+		return render()
+	}
+	throttled := !c.immediate && now.Sub(c.renderedAt) < c.interval
+	if c.view != "" && (!c.dirty || throttled) {
+		return c.view
+	}
+	c.view = render()
+	c.renderedAt = now
+	c.dirty = false
+	c.immediate = false
+	return c.view
+}
+
+// panel returns the memoized rendering of a panel if its key is unchanged, and calls render otherwise.
+// - id: Identifies the panel, e.g. "pf/Grafana (MC)".
+// - key: Describes every input the rendering depends on.
+func (c *renderCache) panel(id, key string, render func() string) string {
+	if c == nil {
+		return render()
+	}
+	if cached, ok := c.panels[id]; ok && cached.key == key {
+		return cached.view
+	}
+	view := render()
+	c.panels[id] = cachedPanel{key: key, view: view}
+	return view
+}
+
+// portForwardPanelKey describes the inputs of renderPortForwardPanel.
+func portForwardPanelKey(m model, pf *portForwardProcess, width int) string {
+	state, reason := portForwardHealth(m, pf)
+	return fmt.Sprintf("%d|%v|%v|%s|%s|%s|%s|%v|%v|%s|%s",
+		width, lipgloss.HasDarkBackground(), pf.label == m.focusedPanelKey,
+		pf.label, pf.port, pf.service, pf.statusMsg, pf.err != nil, pf.forwardingEstablished, state, reason)
+}
+
+// contextPaneKey describes the inputs of renderMcPane and renderWcPane.
+func contextPaneKey(m model, width int, health clusterHealthInfo) string {
+	return fmt.Sprintf("%d|%v|%s|%s|%s|%s|%d/%d|%v|%v|%s",
+		width, lipgloss.HasDarkBackground(), m.focusedPanelKey, m.managementCluster, m.workloadCluster, m.currentKubeContext,
+		health.ReadyNodes, health.TotalNodes, health.StatusError, health.IsLoading, health.LastUpdated.Format(time.RFC3339))
+}
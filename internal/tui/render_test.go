@@ -0,0 +1,54 @@
+package tui
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRenderCacheThrottlesBackgroundUpdates(t *testing.T) {
+	c := newRenderCache()
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	renders := 0
+	render := func() string { renders++; return "frame" }
+
+	c.frame(start, render)
+	// A background update within the frame interval is held back and a tick is scheduled.
+	c.invalidate(false)
+	if c.scheduleFrame(start.Add(10*time.Millisecond)) == nil {
+		t.Fatal("expected a frame tick to be scheduled")
+	}
+	c.frame(start.Add(10*time.Millisecond), render)
+	if renders != 1 {
+		t.Fatalf("expected the frame to be throttled, got %d renders", renders)
+	}
+	// Further updates do not schedule another tick.
+	c.invalidate(false)
+	if c.scheduleFrame(start.Add(20*time.Millisecond)) != nil {
+		t.Fatal("expected no second tick while one is pending")
+	}
+	// The tick redraws.
+	c.handleFrameTick()
+	c.frame(start.Add(frameInterval), render)
+	if renders != 2 {
+		t.Fatalf("expected a redraw after the tick, got %d renders", renders)
+	}
+	// Without changes the cached frame is reused; user input redraws at once.
+	c.frame(start.Add(time.Second), render)
+	c.invalidate(true)
+	c.frame(start.Add(time.Second+time.Millisecond), render)
+	if renders != 3 {
+		t.Fatalf("expected exactly one redraw for user input, got %d renders", renders)
+	}
+}
+
+func TestRenderCachePanelMemoization(t *testing.T) {
+	c := newRenderCache()
+	renders := 0
+	render := func() string { renders++; return "panel" }
+	c.panel("pf/a", "k1", render)
+	c.panel("pf/a", "k1", render)
+	c.panel("pf/a", "k2", render)
+	if renders != 2 {
+		t.Fatalf("expected 2 renders, got %d", renders)
+	}
+}
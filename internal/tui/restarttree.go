@@ -0,0 +1,272 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/giantswarm/envctl/internal/utils"
+)
+
+// restartTreeTimeout bounds how long a restart tree waits for its port-forwards to report an outcome.
+const restartTreeTimeout = 2 * time.Minute
+
+// Actions of a restart tree plan in addition to the action* constants of the connection switch preview.
+const (
+	actionLogin       = "Log in to"
+	actionCheckHealth = "Check health of"
+)
+
+// restartTreeRun is a restart of a cluster connection together with everything that depends on it:
+// the cluster is logged into again first, then its port-forwards are restarted. The plan is shown
+// for confirmation before anything happens; afterwards the outcome of every step is collected and
+// logged as a summary.
+type restartTreeRun struct {
+	id        string               // Correlation ID of the operation.
+	cluster   string               // Cluster identifier used for tsh login, e.g. "myinstallation-mywc".
+	forMC     bool                 // True if the cluster is the management cluster.
+	plan      []plannedAction      // Steps shown for confirmation, in execution order.
+	started   bool                 // False while the plan awaits confirmation.
+	pending   map[string]bool      // Port-forwards restarted by the run that have not reported an outcome yet.
+	outcomes  []restartTreeOutcome // Per-step outcomes in the order they happened.
+	startedAt time.Time
+}
+
+// restartTreeOutcome is the result of a single step of a restart tree.
+type restartTreeOutcome struct {
+	Step   string // The step, e.g. "Restart Grafana (MC)".
+	OK     bool   // True if the step succeeded.
+	Detail string // e.g. "running", "skipped" or the error.
+}
+
+// String formats the outcome for the summary, e.g. "Restart Grafana (MC): running".
+func (o restartTreeOutcome) String() string {
+	return fmt.Sprintf("%s: %s", o.Step, o.Detail)
+}
+
+// addOutcome records the outcome of a step.
+func (r *restartTreeRun) addOutcome(step string, ok bool, detail string) {
+	r.outcomes = append(r.outcomes, restartTreeOutcome{Step: step, OK: ok, Detail: detail})
+}
+
+// restartTreeLoginMsg carries the result of the login step of a restart tree.
+type restartTreeLoginMsg struct {
+	id     string
+	stderr string
+	err    error
+}
+
+// restartTreeTimeoutMsg ends a restart tree whose port-forwards did not all report in time.
+type restartTreeTimeoutMsg struct {
+	id string
+}
+
+// planRestartTree computes the steps of restarting a cluster and its dependent port-forwards.
+// - forMC: True for the management cluster, false for the workload cluster.
+// Returns the cluster identifier used for login and the planned actions in execution order.
+func planRestartTree(m model, forMC bool) (string, []plannedAction) {
+	cluster := m.getManagementClusterContextIdentifier()
+	if !forMC {
+		cluster = m.getWorkloadClusterContextIdentifier()
+	}
+	plan := []plannedAction{
+		{Action: actionLogin, Target: cluster, Detail: "tsh kube login"},
+		{Action: actionCheckHealth, Target: cluster},
+	}
+	key := mcPaneFocusKey
+	if !forMC {
+		key = wcPaneFocusKey
+	}
+	closure, _ := dependencyClosureOf(m, key)
+	for _, node := range closure.Downstream {
+		if node.Kind == nodePortForward {
+			pf := m.portForwards[node.Key]
+			estimate, _ := averageStartupTime(pf)
+			plan = append(plan, plannedAction{Action: actionRestart, Target: node.Name, Detail: pf.port, Estimate: estimate})
+		}
+	}
+	return cluster, plan
+}
+
+// requestRestartTree prepares a restart tree for the focused cluster pane and asks for confirmation,
+// or starts it right away if confirmations are disabled (--force).
+func requestRestartTree(m model) (model, tea.Cmd) {
+	if m.restartTree != nil && m.restartTree.started {
+		m.combinedOutput.Append(fmt.Sprintf("[SYSTEM] %sA restart is still in progress.", correlationTag(m.restartTree.id)))
+		return m, nil
+	}
+	var forMC bool
+	switch m.focusedPanelKey {
+	case mcPaneFocusKey:
+		forMC = true
+	case wcPaneFocusKey:
+		forMC = false
+	default:
+		m.combinedOutput.Append("[SYSTEM] Focus a cluster pane to restart it with its port-forwards; use 'r' to restart a single port-forward.")
+		return m, nil
+	}
+	cluster, plan := planRestartTree(m, forMC)
+	if cluster == "" {
+		return m, nil
+	}
+	m.restartTree = &restartTreeRun{id: newCorrelationID(), cluster: cluster, forMC: forMC, plan: plan}
+	if m.autoConfirm {
+		return startRestartTree(m)
+	}
+	return m, nil
+}
+
+// startRestartTree runs a confirmed restart tree: it logs the plan and starts the login step.
+// The dependent port-forwards are restarted once the login succeeded, see handleRestartTreeLoginMsg.
+func startRestartTree(m model) (model, tea.Cmd) {
+	run := m.restartTree
+	run.started = true
+	run.startedAt = time.Now()
+	run.pending = make(map[string]bool)
+	tag := correlationTag(run.id)
+	m.combinedOutput.Append(fmt.Sprintf("[SYSTEM] %sRestarting cluster %s and its dependents:", tag, run.cluster))
+	for i, action := range run.plan {
+		m.combinedOutput.Append(fmt.Sprintf("[SYSTEM] %s  %d. %s", tag, i+1, action))
+	}
+
+	id, cluster := run.id, run.cluster
+	login := func() tea.Msg {
+		_, stderr, err := utils.LoginToKubeCluster(cluster)
+		return restartTreeLoginMsg{id: id, stderr: stderr, err: err}
+	}
+	timeout := tea.Tick(restartTreeTimeout, func(time.Time) tea.Msg { return restartTreeTimeoutMsg{id: id} })
+	return m, tea.Batch(login, timeout)
+}
+
+// handleRestartTreeLoginMsg continues a restart tree after its login step: on success the cluster health
+// is re-checked and the dependent port-forwards are restarted; on failure they are skipped.
+func handleRestartTreeLoginMsg(m model, msg restartTreeLoginMsg) (model, tea.Cmd) {
+	run := m.restartTree
+	if run == nil || run.id != msg.id {
+		return m, nil // Stale result of a finished run.
+	}
+	if msg.err != nil {
+		run.addOutcome(actionLogin+" "+run.cluster, false, fmt.Sprintf("failed: %v", msg.err))
+		for _, action := range run.plan[1:] {
+			run.addOutcome(action.Action+" "+action.Target, false, "skipped")
+		}
+		finishRestartTree(&m)
+		return m, nil
+	}
+	run.addOutcome(actionLogin+" "+run.cluster, true, "ok")
+
+	cmds := []tea.Cmd{}
+	clusterName := m.managementCluster
+	if run.forMC {
+		m.MCHealth.IsLoading = true
+	} else {
+		clusterName = m.workloadCluster
+		m.WCHealth.IsLoading = true
+	}
+	cmds = append(cmds, fetchNodeStatusCmd(run.cluster, run.forMC, clusterName))
+	run.addOutcome(actionCheckHealth+" "+run.cluster, true, "requested")
+
+	n := 0
+	for _, action := range run.plan {
+		if action.Action != actionRestart {
+			continue
+		}
+		pf, ok := m.portForwards[action.Target]
+		if !ok {
+			run.addOutcome(actionRestart+" "+action.Target, false, "skipped (no longer configured)")
+			continue
+		}
+		n++
+		if cmd := restartPortForward(&m, pf, restartDependency, childCorrelationID(run.id, n), "restart of cluster "+run.cluster); cmd != nil {
+			run.pending[pf.label] = true
+			cmds = append(cmds, cmd)
+		} else {
+			run.addOutcome(actionRestart+" "+pf.label, false, "failed to start")
+		}
+	}
+	if len(run.pending) == 0 {
+		finishRestartTree(&m)
+	}
+	return m, tea.Batch(cmds...)
+}
+
+// trackRestartTreeOutcome records the outcome of a port-forward restarted by the running restart tree
+// once it is running or has failed, and finishes the run when every port-forward has reported.
+func trackRestartTreeOutcome(m *model, pf *portForwardProcess) {
+	run := m.restartTree
+	if run == nil || !run.pending[pf.label] {
+		return
+	}
+	switch portForwardState(pf) {
+	case pfStateRunning:
+		run.addOutcome(actionRestart+" "+pf.label, true, "running")
+	case pfStateFailed:
+		reason := pf.lastError
+		if reason == "" {
+			reason = pf.statusMsg
+		}
+		run.addOutcome(actionRestart+" "+pf.label, false, "failed: "+reason)
+	default:
+		return
+	}
+	delete(run.pending, pf.label)
+	if len(run.pending) == 0 {
+		finishRestartTree(m)
+	}
+}
+
+// handleRestartTreeTimeoutMsg finishes a restart tree whose port-forwards did not all report an outcome.
+func handleRestartTreeTimeoutMsg(m model, msg restartTreeTimeoutMsg) model {
+	run := m.restartTree
+	if run == nil || run.id != msg.id || !run.started {
+		return m
+	}
+	for _, label := range sortedKeys(run.pending) {
+		run.addOutcome(actionRestart+" "+label, false, fmt.Sprintf("no result within %s", restartTreeTimeout))
+	}
+	finishRestartTree(&m)
+	return m
+}
+
+// finishRestartTree logs the per-step outcome summary of the running restart tree and clears it.
+func finishRestartTree(m *model) {
+	run := m.restartTree
+	tag := correlationTag(run.id)
+	failed := 0
+	for _, outcome := range run.outcomes {
+		if !outcome.OK {
+			failed++
+		}
+	}
+	m.combinedOutput.Append(fmt.Sprintf("[SYSTEM] %sRestart of %s finished in %s, %d of %d steps not successful:",
+		tag, run.cluster, time.Since(run.startedAt).Round(time.Second), failed, len(run.outcomes)))
+	for _, outcome := range run.outcomes {
+		m.combinedOutput.Append(fmt.Sprintf("[SYSTEM] %s  %s", tag, outcome))
+	}
+	m.restartTree = nil
+}
+
+// handleKeyMsgRestartTreeConfirm processes key presses while a restart tree plan awaits confirmation.
+// Enter or 'y' starts it, Esc or 'n' cancels it.
+func handleKeyMsgRestartTreeConfirm(m model, keyMsg tea.KeyMsg) (model, tea.Cmd) {
+	switch keyMsg.String() {
+	case "enter", "y":
+		return startRestartTree(m)
+	case "esc", "n":
+		m.combinedOutput.Append(fmt.Sprintf("[SYSTEM] Restart of %s cancelled.", m.restartTree.cluster))
+		m.restartTree = nil
+	}
+	return m, nil
+}
+
+// sortedKeys returns the keys of a set in lexical order.
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
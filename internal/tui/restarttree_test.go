@@ -0,0 +1,117 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func restartTreeTestModel() model {
+	return model{
+		managementCluster: "alpha",
+		workloadCluster:   "beta",
+		portForwards: map[string]*portForwardProcess{
+			"Grafana (MC)":       {label: "Grafana (MC)", port: "3000:3000"},
+			"Alloy Metrics (WC)": {label: "Alloy Metrics (WC)", port: "12345:12345", isWC: true},
+		},
+		portForwardOrder: []string{mcPaneFocusKey, wcPaneFocusKey, "Grafana (MC)", "Alloy Metrics (WC)"},
+		focusedPanelKey:  wcPaneFocusKey,
+		combinedOutput:   newLogBuffer(0, 0),
+	}
+}
+
+func TestPlanRestartTree(t *testing.T) {
+	m := restartTreeTestModel()
+
+	cluster, plan := planRestartTree(m, false)
+	if cluster != "alpha-beta" {
+		t.Fatalf("cluster = %q, want alpha-beta", cluster)
+	}
+	want := []plannedAction{
+		{Action: actionLogin, Target: "alpha-beta", Detail: "tsh kube login"},
+		{Action: actionCheckHealth, Target: "alpha-beta"},
+		{Action: actionRestart, Target: "Alloy Metrics (WC)", Detail: "12345:12345"},
+	}
+	if len(plan) != len(want) {
+		t.Fatalf("plan = %v, want %v", plan, want)
+	}
+	for i := range want {
+		if plan[i] != want[i] {
+			t.Errorf("plan[%d] = %v, want %v", i, plan[i], want[i])
+		}
+	}
+}
+
+func TestRestartTreeConfirmAndCancel(t *testing.T) {
+	m := restartTreeTestModel()
+
+	m, _ = requestRestartTree(m)
+	if m.restartTree == nil || m.restartTree.started || m.restartTree.forMC {
+		t.Fatalf("expected an unconfirmed WC restart tree, got %+v", m.restartTree)
+	}
+	m, _ = handleKeyMsgRestartTreeConfirm(m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.restartTree != nil {
+		t.Fatal("expected the restart tree to be cancelled")
+	}
+
+	m.focusedPanelKey = "Grafana (MC)"
+	m, _ = requestRestartTree(m)
+	if m.restartTree != nil {
+		t.Fatal("expected no restart tree without a focused cluster pane")
+	}
+}
+
+func TestRestartTreeLoginFailureSkipsDependents(t *testing.T) {
+	m := restartTreeTestModel()
+	m, _ = requestRestartTree(m)
+	m, _ = startRestartTree(m)
+	id := m.restartTree.id
+
+	// A result of another run is ignored.
+	m, _ = handleRestartTreeLoginMsg(m, restartTreeLoginMsg{id: "other", err: errors.New("boom")})
+	if m.restartTree == nil {
+		t.Fatal("stale login result finished the run")
+	}
+
+	m, _ = handleRestartTreeLoginMsg(m, restartTreeLoginMsg{id: id, err: errors.New("expired")})
+	if m.restartTree != nil {
+		t.Fatal("expected the run to finish after a failed login")
+	}
+	log := strings.Join(m.combinedOutput.Lines(), "\n")
+	for _, want := range []string{"3 of 3 steps not successful", "Log in to alpha-beta: failed: expired", "Restart Alloy Metrics (WC): skipped"} {
+		if !strings.Contains(log, want) {
+			t.Errorf("log missing %q:\n%s", want, log)
+		}
+	}
+}
+
+func TestRestartTreeTracksPortForwardOutcomes(t *testing.T) {
+	m := restartTreeTestModel()
+	m.focusedPanelKey = mcPaneFocusKey
+	m, _ = requestRestartTree(m)
+	m, _ = startRestartTree(m)
+	m.restartTree.pending["Grafana (MC)"] = true
+
+	// A port-forward not restarted by the run is not tracked.
+	trackRestartTreeOutcome(&m, m.portForwards["Alloy Metrics (WC)"])
+
+	pf := m.portForwards["Grafana (MC)"]
+	pf.active = true
+	pf.statusMsg = "Initializing..."
+	trackRestartTreeOutcome(&m, pf)
+	if m.restartTree == nil || len(m.restartTree.outcomes) != 0 {
+		t.Fatal("a starting port-forward must not be recorded yet")
+	}
+
+	pf.statusMsg = "Running"
+	pf.forwardingEstablished = true
+	trackRestartTreeOutcome(&m, pf)
+	if m.restartTree != nil {
+		t.Fatal("expected the run to finish once every port-forward reported")
+	}
+	if !strings.Contains(m.combinedOutput.String(), "Restart Grafana (MC): running") {
+		t.Errorf("summary missing the port-forward outcome:\n%s", m.combinedOutput.String())
+	}
+}
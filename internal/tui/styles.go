@@ -35,6 +35,13 @@ var (
 			Padding(0, 2).
 			MarginBottom(0)
 
+	// readOnlyBannerStyle is for the banner shown below the header when the TUI runs in read-only mode.
+	readOnlyBannerStyle = lipgloss.NewStyle().
+				Bold(true).
+				Foreground(lipgloss.AdaptiveColor{Light: "#000000", Dark: "#000000"}).
+				Background(lipgloss.AdaptiveColor{Light: "#FFD966", Dark: "#E5B800"}).
+				Padding(0, 2)
+
 	// panelStyle is the base style for all rectangular panels (e.g., port forwards, context info).
 	// It defines default border and padding.
 	panelStyle = lipgloss.NewStyle().
@@ -93,6 +100,9 @@ var (
 			Padding(0, 1).
 			Margin(0, 1, 0, 0)
 
+	// newConnectionInputStyle frames the new connection input and its impact preview.
+	newConnectionInputStyle = lipgloss.NewStyle().Padding(1, 2).Border(lipgloss.RoundedBorder()).Align(lipgloss.Center)
+
 	// --- Log Overlay Styles (similar to Help Overlay) ---
 	logOverlayStyle = lipgloss.NewStyle().
 			Border(lipgloss.RoundedBorder()).
@@ -109,8 +119,14 @@ var (
 	panelStatusAttemptingStyle   = panelStatusInitializingStyle.Copy() // Typically same as initializing
 	panelStatusRunningStyle      = panelStyle.Copy().Background(lipgloss.AdaptiveColor{Light: "#D4EFDF", Dark: "#1A3A1A"}).BorderForeground(lipgloss.AdaptiveColor{Light: "#307030", Dark: "#60A060"})
 	panelStatusErrorStyle        = panelStyle.Copy().Background(lipgloss.AdaptiveColor{Light: "#FADBD8", Dark: "#4D2A2A"}).BorderForeground(lipgloss.AdaptiveColor{Light: "#A04040", Dark: "#B07070"})
+	panelStatusDegradedStyle     = panelStyle.Copy().Background(lipgloss.AdaptiveColor{Light: "#FFE5CC", Dark: "#4D3A1F"}).BorderForeground(lipgloss.AdaptiveColor{Light: "#C06000", Dark: "#E09040"})
 	panelStatusExitedStyle       = panelStyle.Copy().Background(lipgloss.AdaptiveColor{Light: "#FCF3CF", Dark: "#4D4D2A"}).BorderForeground(lipgloss.AdaptiveColor{Light: "#A07030", Dark: "#B0A070"})
 
+	// logPanelStyle is the panel around the activity log in the main view.
+	logPanelStyle = panelStatusDefaultStyle.Copy().
+			BorderForeground(lipgloss.AdaptiveColor{Light: "#606060", Dark: "#A0A0A0"}).
+			Background(lipgloss.AdaptiveColor{Light: "#F8F8F8", Dark: "#2A2A3A"})
+
 	// --- Focused Panel Background Styles based on Status ---
 	// Similar to the above, but these apply when a panel is focused.
 	focusedPanelStatusDefaultStyle = panelStatusDefaultStyle.Copy().
@@ -131,6 +147,10 @@ var (
 					Inherit(focusedPanelStyle).
 					Background(lipgloss.AdaptiveColor{Light: "#FFDBDB", Dark: "#582F2F"})
 
+	focusedPanelStatusDegradedStyle = panelStatusDegradedStyle.Copy().
+					Inherit(focusedPanelStyle).
+					Background(lipgloss.AdaptiveColor{Light: "#FFE0C0", Dark: "#5A4224"})
+
 	focusedPanelStatusExitedStyle = panelStatusExitedStyle.Copy().
 					Inherit(focusedPanelStyle).
 					Background(lipgloss.AdaptiveColor{Light: "#FFF3CF", Dark: "#574F2F"})
@@ -140,6 +160,7 @@ var (
 	statusMsgInitializingStyle = lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#000080", Dark: "#B0D8FF"}) // Darker blue / Lighter blue
 	statusMsgRunningStyle      = lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#004400", Dark: "#C0FFC0"}) // Darker green / Lighter green
 	statusMsgErrorStyle        = lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#880000", Dark: "#FFABAB"}) // Darker red / Lighter red
+	statusMsgDegradedStyle     = lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#804000", Dark: "#FFC890"}) // Darker amber / Lighter amber
 	statusMsgExitedStyle       = lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#553300", Dark: "#FFE0B0"}) // Darker orange / Lighter orange
 
 	// --- Context Pane Styles (for MC and WC info panes) ---
@@ -167,4 +188,8 @@ var (
 	healthGoodStyle    = lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#005500", Dark: "#90FF90"}).Bold(true) // Brighter green in dark mode
 	healthWarnStyle    = lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#703000", Dark: "#FFFF00"}).Bold(true) // Bright yellow in dark mode
 	healthErrorStyle   = lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#990000", Dark: "#FF9090"}).Bold(true) // Brighter red in dark mode
+
+	// --- Port Forward Page Indicator Styles ---
+	pfPageIndicatorStyle      = lipgloss.NewStyle().Padding(0, 1).Foreground(lipgloss.AdaptiveColor{Light: "#505050", Dark: "#B0B0B0"})
+	pfPageIndicatorAlertStyle = pfPageIndicatorStyle.Copy().Bold(true).Foreground(lipgloss.AdaptiveColor{Light: "#990000", Dark: "#FF9090"})
 )
@@ -0,0 +1,302 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// tableRestartWindow is the window over which the table view counts restarts.
+const tableRestartWindow = time.Hour
+
+// Columns the table view can be sorted by, in the order 'o' cycles through them.
+const (
+	tableSortName = iota
+	tableSortState
+	tableSortRestarts
+	tableSortUptime
+	tableSortColumns
+)
+
+// tableSortNames names the sort columns in the table header.
+var tableSortNames = [tableSortColumns]string{"name", "state", "restarts", "uptime"}
+
+// pfTable holds the state of the port-forward table view, an alternative to the panels
+// once there are too many port-forwards to take in at a glance.
+type pfTable struct {
+	visible   bool
+	filter    string          // Fuzzy filter typed after '/'.
+	filtering bool            // True while keys extend the filter.
+	sortBy    int             // One of the tableSort* columns.
+	desc      bool            // Reverses the sort order.
+	cursor    int             // Index of the highlighted row in the filtered, sorted rows.
+	selected  map[string]bool // Labels selected for bulk actions.
+}
+
+// pfTableRow is a port-forward as shown in the table view.
+type pfTableRow struct {
+	label    string
+	cluster  string // "MC" or "WC".
+	state    string // As reported by portForwardHealth, so Degraded is visible.
+	restarts int    // Restarts within tableRestartWindow.
+	uptime   time.Duration
+	port     string
+}
+
+// portForwardUptime returns how long a running port-forward has been running since it was last (re)started,
+// or zero if it is not running.
+func portForwardUptime(pf *portForwardProcess, now time.Time) time.Duration {
+	if pf.runningSince.IsZero() || portForwardState(pf) != pfStateRunning {
+		return 0
+	}
+	return now.Sub(pf.runningSince)
+}
+
+// fuzzyMatch reports whether the characters of query appear in s in order, ignoring case.
+func fuzzyMatch(s, query string) bool {
+	s, query = strings.ToLower(s), strings.ToLower(query)
+	for _, r := range query {
+		i := strings.IndexRune(s, r)
+		if i < 0 {
+			return false
+		}
+		s = s[i+len(string(r)):]
+	}
+	return true
+}
+
+// tableRows returns the port-forwards matching the table filter, sorted by the selected column.
+// The filter is matched against the label, cluster, state, namespace and service, and "ephemeral" for
+// ephemeral port-forwards. Ties are broken by label so the order is stable.
+func tableRows(m model, now time.Time) []pfTableRow {
+	var rows []pfTableRow
+	for _, label := range portForwardPanelKeys(m) {
+		pf, ok := m.portForwards[label]
+		if !ok {
+			continue
+		}
+		row := pfTableRow{
+			label:    label,
+			cluster:  "MC",
+			restarts: countRestarts(pf, tableRestartWindow, now),
+			uptime:   portForwardUptime(pf, now),
+			port:     pf.port,
+		}
+		if pf.isWC {
+			row.cluster = "WC"
+		}
+		row.state, _ = portForwardHealth(m, pf)
+		haystack := strings.Join([]string{label, row.cluster, row.state, pf.namespace, pf.service}, " ")
+		if pf.ephemeral {
+			haystack += " ephemeral"
+		}
+		if !fuzzyMatch(haystack, m.table.filter) {
+			continue
+		}
+		rows = append(rows, row)
+	}
+
+	less := func(a, b pfTableRow) bool {
+		switch m.table.sortBy {
+		case tableSortState:
+			if a.state != b.state {
+				return a.state < b.state
+			}
+		case tableSortRestarts:
+			if a.restarts != b.restarts {
+				return a.restarts < b.restarts
+			}
+		case tableSortUptime:
+			if a.uptime != b.uptime {
+				return a.uptime < b.uptime
+			}
+		}
+		return a.label < b.label
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		if m.table.desc {
+			return less(rows[j], rows[i])
+		}
+		return less(rows[i], rows[j])
+	})
+	return rows
+}
+
+// tableTargets returns the labels a bulk action applies to: the selected rows that are still shown,
+// or the highlighted row if nothing is selected.
+func tableTargets(m model, rows []pfTableRow) []string {
+	var labels []string
+	for _, row := range rows {
+		if m.table.selected[row.label] {
+			labels = append(labels, row.label)
+		}
+	}
+	if len(labels) == 0 && m.table.cursor < len(rows) {
+		labels = append(labels, rows[m.table.cursor].label)
+	}
+	return labels
+}
+
+// trimLastRune removes the last character of s, which may take several bytes, e.g. "é".
+func trimLastRune(s string) string {
+	_, size := utf8.DecodeLastRuneInString(s)
+	return s[:len(s)-size]
+}
+
+// handleKeyMsgTable processes key presses while the table view is open.
+// - '/': Edit the filter; Enter keeps it, Esc clears it.
+// - Up/Down ('k'/'j'): Move the highlight. Space selects the highlighted row, 'a' selects all shown rows or none.
+// - 'o': Sort by the next column. 'O': Reverse the sort order.
+// - 'r': (Re)start the selected port-forwards. 's': Stop them.
+// - Esc or 'T': Close the table view.
+func handleKeyMsgTable(m model, keyMsg tea.KeyMsg) (model, tea.Cmd) {
+	rows := tableRows(m, time.Now())
+
+	if m.table.filtering {
+		switch keyMsg.Type {
+		case tea.KeyEnter:
+			m.table.filtering = false
+		case tea.KeyEsc:
+			m.table.filtering = false
+			m.table.filter = ""
+		case tea.KeyBackspace:
+			m.table.filter = trimLastRune(m.table.filter)
+		case tea.KeyRunes, tea.KeySpace:
+			m.table.filter += string(keyMsg.Runes)
+		}
+		m.table.cursor = 0
+		return m, nil
+	}
+
+	switch keyMsg.String() {
+	case "esc", "T":
+		m.table.visible = false
+	case "/":
+		m.table.filtering = true
+	case "up", "k":
+		if m.table.cursor > 0 {
+			m.table.cursor--
+		}
+	case "down", "j":
+		if m.table.cursor < len(rows)-1 {
+			m.table.cursor++
+		}
+	case " ":
+		if m.table.cursor < len(rows) {
+			label := rows[m.table.cursor].label
+			m.table.selected[label] = !m.table.selected[label]
+		}
+	case "a":
+		all := len(rows) > 0
+		for _, row := range rows {
+			all = all && m.table.selected[row.label]
+		}
+		m.table.selected = make(map[string]bool)
+		if !all {
+			for _, row := range rows {
+				m.table.selected[row.label] = true
+			}
+		}
+	case "o":
+		m.table.sortBy = (m.table.sortBy + 1) % tableSortColumns
+	case "O":
+		m.table.desc = !m.table.desc
+	case "r", "s":
+		if m.readOnly {
+			m.combinedOutput.Append(fmt.Sprintf("[SYSTEM] Read-only mode: '%s' is disabled. %s", keyMsg.String(), m.readOnlyReason))
+			return m, nil
+		}
+		labels := tableTargets(m, rows)
+		if len(labels) == 0 {
+			return m, nil
+		}
+		correlationID := newCorrelationID()
+		var cmds []tea.Cmd
+		for i, label := range labels {
+			pf := m.portForwards[label]
+			if keyMsg.String() == "s" {
+				stopPortForward(&m, pf, "stopped from the table view")
+				continue
+			}
+			if cmd := restartPortForward(&m, pf, restartManual, childCorrelationID(correlationID, i+1), "restarted from the table view"); cmd != nil {
+				cmds = append(cmds, cmd)
+			}
+		}
+		m.table.selected = make(map[string]bool)
+		return m, tea.Batch(cmds...)
+	}
+	return m, nil
+}
+
+// renderTableOverlay renders the table view: the filter, a header naming the sort column and one row per
+// port-forward. Only the rows around the highlighted one are shown if the table does not fit the screen.
+func renderTableOverlay(m model, width, height int) string {
+	now := time.Now()
+	rows := tableRows(m, now)
+
+	var b strings.Builder
+	b.WriteString(helpTitleStyle.Render(fmt.Sprintf("Port Forwards (%d)", len(rows))))
+	b.WriteString("\n")
+	filter := m.table.filter
+	if m.table.filtering {
+		filter += "_"
+	}
+	order := "▲"
+	if m.table.desc {
+		order = "▼"
+	}
+	b.WriteString(fmt.Sprintf("Filter: %s   Sort: %s %s\n\n", filter, tableSortNames[m.table.sortBy], order))
+	b.WriteString(fmt.Sprintf("    %-32s %-3s %-9s %8s %8s  %s\n", "NAME", "CL", "STATE", "RESTARTS", "UPTIME", "PORT"))
+
+	maxRows := height - 14
+	if maxRows < 3 {
+		maxRows = 3
+	}
+	start := 0
+	if m.table.cursor >= maxRows {
+		start = m.table.cursor - maxRows + 1
+	}
+	end := start + maxRows
+	if end > len(rows) {
+		end = len(rows)
+	}
+	if len(rows) == 0 {
+		b.WriteString("    No port forwards match the filter.\n")
+	}
+	for i := start; i < end; i++ {
+		row := rows[i]
+		cursor, mark := " ", " "
+		if i == m.table.cursor {
+			cursor = ">"
+		}
+		if m.table.selected[row.label] {
+			mark = "*"
+		}
+		uptime := "-"
+		if row.uptime > 0 {
+			uptime = formatAge(row.uptime)
+		}
+		line := fmt.Sprintf("%s%s  %-32s %-3s %-9s %8d %8s  %s", cursor, mark, row.label, row.cluster, row.state, row.restarts, uptime, row.port)
+		if i == m.table.cursor {
+			line = helpKeyStyle.Render(line)
+		}
+		b.WriteString(line + "\n")
+	}
+
+	b.WriteString(fmt.Sprintf("\nRestarts within the last %s.\n", tableRestartWindow))
+	b.WriteString("/ filter, ↑/↓ move, Space select, a all, o sort, O reverse, r restart, s stop, Esc close")
+
+	overlayWidth := width * 4 / 5
+	if overlayWidth < 60 {
+		overlayWidth = width
+	}
+	contentWidth := overlayWidth - helpOverlayStyle.GetHorizontalFrameSize()
+	if contentWidth < 0 {
+		contentWidth = 0
+	}
+	return helpOverlayStyle.Copy().Width(contentWidth).Render(b.String())
+}
@@ -0,0 +1,127 @@
+package tui
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func tableTestModel(now time.Time) model {
+	running := func(label string, since time.Time, restarts int) *portForwardProcess {
+		pf := &portForwardProcess{label: label, active: true, forwardingEstablished: true}
+		for i := 0; i < restarts; i++ {
+			pf.history = append(pf.history,
+				stateTransition{At: since.Add(-time.Minute), From: pfStateRunning, To: pfStateStarting},
+				stateTransition{At: since.Add(-time.Minute), From: pfStateStarting, To: pfStateRunning})
+		}
+		pf.history = append(pf.history, stateTransition{At: since, From: pfStateStarting, To: pfStateRunning})
+		pf.runningSince = since
+		return pf
+	}
+	return model{
+		portForwards: map[string]*portForwardProcess{
+			"Grafana (MC)":       running("Grafana (MC)", now.Add(-2*time.Hour), 0),
+			"Prometheus (MC)":    running("Prometheus (MC)", now.Add(-5*time.Minute), 2),
+			"Alloy Metrics (WC)": {label: "Alloy Metrics (WC)", isWC: true, lastError: "connection refused"},
+		},
+		portForwardOrder: []string{mcPaneFocusKey, "Prometheus (MC)", "Grafana (MC)", "Alloy Metrics (WC)"},
+		combinedOutput:   newLogBuffer(0, 0),
+		table:            pfTable{visible: true, selected: make(map[string]bool)},
+	}
+}
+
+func TestFuzzyMatch(t *testing.T) {
+	for _, tt := range []struct {
+		s, query string
+		want     bool
+	}{
+		{"Prometheus (MC)", "prom", true},
+		{"Prometheus (MC)", "pmc", true},
+		{"Grafana (MC)", "wc", false},
+		{"anything", "", true},
+	} {
+		if got := fuzzyMatch(tt.s, tt.query); got != tt.want {
+			t.Errorf("fuzzyMatch(%q, %q) = %v, want %v", tt.s, tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestTableRowsSortAndFilter(t *testing.T) {
+	now := time.Now()
+	m := tableTestModel(now)
+
+	labels := func(rows []pfTableRow) []string {
+		var out []string
+		for _, r := range rows {
+			out = append(out, r.label)
+		}
+		return out
+	}
+	assertOrder := func(want ...string) {
+		t.Helper()
+		got := labels(tableRows(m, now))
+		if len(got) != len(want) {
+			t.Fatalf("rows = %v, want %v", got, want)
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Fatalf("rows = %v, want %v", got, want)
+			}
+		}
+	}
+
+	assertOrder("Alloy Metrics (WC)", "Grafana (MC)", "Prometheus (MC)")
+	m.table.sortBy, m.table.desc = tableSortRestarts, true
+	assertOrder("Prometheus (MC)", "Grafana (MC)", "Alloy Metrics (WC)")
+	m.table.sortBy, m.table.desc = tableSortUptime, true
+	assertOrder("Grafana (MC)", "Prometheus (MC)", "Alloy Metrics (WC)")
+
+	m.table.filter = "failed"
+	assertOrder("Alloy Metrics (WC)")
+}
+
+func TestTableBulkStop(t *testing.T) {
+	m := tableTestModel(time.Now())
+	stop := make(chan struct{})
+	m.portForwards["Grafana (MC)"].stopChan = stop
+
+	// Select all MC port-forwards through the filter, then stop them.
+	m, _ = handleKeyMsgTable(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/")})
+	m, _ = handleKeyMsgTable(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("(mc)")})
+	m, _ = handleKeyMsgTable(m, tea.KeyMsg{Type: tea.KeyEnter})
+	m, _ = handleKeyMsgTable(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+	if len(m.table.selected) != 2 {
+		t.Fatalf("selected = %v, want the two MC port-forwards", m.table.selected)
+	}
+	m, _ = handleKeyMsgTable(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+
+	for _, label := range []string{"Grafana (MC)", "Prometheus (MC)"} {
+		if pf := m.portForwards[label]; pf.active || portForwardState(pf) != pfStateStopped {
+			t.Errorf("%s not stopped: %+v", label, pf)
+		}
+	}
+	select {
+	case <-stop:
+	default:
+		t.Error("expected the stop channel to be closed")
+	}
+	if len(m.table.selected) != 0 {
+		t.Error("expected the selection to be cleared after the action")
+	}
+}
+
+func TestTableFilterBackspaceMultiByte(t *testing.T) {
+	m := tableTestModel(time.Now())
+	m, _ = handleKeyMsgTable(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/")})
+	m, _ = handleKeyMsgTable(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("gé")})
+	m, _ = handleKeyMsgTable(m, tea.KeyMsg{Type: tea.KeyBackspace})
+	if m.table.filter != "g" {
+		t.Errorf("filter = %q, want %q", m.table.filter, "g")
+	}
+	m, _ = handleKeyMsgTable(m, tea.KeyMsg{Type: tea.KeyBackspace})
+	m, _ = handleKeyMsgTable(m, tea.KeyMsg{Type: tea.KeyBackspace})
+	if m.table.filter != "" || !m.table.filtering {
+		t.Errorf("filter = %q, filtering = %v after clearing", m.table.filter, m.table.filtering)
+	}
+}
@@ -0,0 +1,193 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// triageLogLines is the number of recent activity log lines searched for failure signatures.
+const triageLogLines = 100
+
+// failureSignature is a known failure pattern with its likely cause and the suggested fix.
+type failureSignature struct {
+	patterns []string // Lower-case substrings of an error, status or log line.
+	cause    string
+	fix      string
+	weight   int // How specific the signature is; more specific signatures rank first.
+}
+
+// failureSignatures are the common failure patterns recognised by triageLastFailure, most specific first.
+var failureSignatures = []failureSignature{
+	{
+		patterns: []string{"certificate has expired", "certificate expired", "tsh login", "relogin", "not logged in", "unauthorized", "the server has asked for the client to provide credentials"},
+		cause:    "Teleport credentials have expired or are missing",
+		fix:      "log in again with 'n' or run 'tsh kube login <cluster>', then restart with 'R' on the cluster pane",
+		weight:   30,
+	},
+	{
+		patterns: []string{"address already in use", "only one usage of each socket address"},
+		cause:    "the local port is used by another process",
+		fix:      "find it with 'lsof -i :<port>' and stop it (or the other envctl instance), then press 'r'",
+		weight:   30,
+	},
+	{
+		patterns: []string{"imagepullbackoff", "errimagepull", "failed to pull image"},
+		cause:    "the target pods cannot pull their image",
+		fix:      "check the pod events with 'kubectl describe pod' in the service's namespace",
+		weight:   25,
+	},
+	{
+		patterns: []string{"no ready pods", "no pods found"},
+		cause:    "the target has no ready pods",
+		fix:      "check the pods in the target's namespace with kubectl; press 'r' once they are ready",
+		weight:   20,
+	},
+	{
+		patterns: []string{"proxy error via"},
+		cause:    "the HTTP proxy rejected or failed the connection",
+		fix:      "check HTTPS_PROXY/NO_PROXY",
+		weight:   20,
+	},
+	{
+		patterns: []string{"i/o timeout", "connection refused", "no such host", "network is unreachable", "timed out", "connection reset"},
+		cause:    "the cluster API is unreachable",
+		fix:      "check your network or VPN, then restart with 'R' on the cluster pane",
+		weight:   10,
+	},
+	{
+		patterns: []string{"not found"},
+		cause:    "the target service or namespace does not exist in this cluster",
+		fix:      "check that the cluster runs the expected monitoring stack",
+		weight:   5,
+	},
+}
+
+// likelyCause is a failure signature matched during triage.
+type likelyCause struct {
+	signature failureSignature
+	score     int    // Signature weight plus the number of matching lines.
+	evidence  string // First matching line.
+}
+
+// triageLastFailure inspects the most recently failed port-forward, or a failing cluster if no port-forward
+// has failed, and ranks the likely causes. It gathers the last error, the cluster the port-forward depends on,
+// its recent state transitions and activity log lines, and matches them against failureSignatures.
+// - m: The current TUI model.
+// Returns the triage report as lines for the activity log.
+func triageLastFailure(m model) []string {
+	var failed *portForwardProcess
+	for _, label := range m.portForwardOrder {
+		pf, ok := m.portForwards[label]
+		if !ok || portForwardState(pf) != pfStateFailed {
+			continue
+		}
+		if failed == nil || pf.lastErrorAt.After(failed.lastErrorAt) {
+			failed = pf
+		}
+	}
+
+	var lines, evidence []string
+	var clusterName string
+	var health clusterHealthInfo
+	if failed != nil {
+		clusterName, health = m.clusterForPortForward(failed)
+		lastError := failed.lastError
+		if lastError == "" && failed.err != nil {
+			lastError = failed.err.Error()
+		}
+		lines = append(lines, fmt.Sprintf("Most recent failure: %s at %s: %s", failed.label, failed.lastErrorAt.Format("15:04:05"), lastError))
+		evidence = append(evidence, lastError, failed.statusMsg)
+		for _, t := range failed.history {
+			evidence = append(evidence, t.Reason)
+		}
+		prefix := "[" + failed.label
+		logLines := m.combinedOutput.Lines()
+		if len(logLines) > triageLogLines {
+			logLines = logLines[len(logLines)-triageLogLines:]
+		}
+		for _, line := range logLines {
+			if strings.HasPrefix(line, prefix) {
+				evidence = append(evidence, line)
+			}
+		}
+	} else {
+		switch {
+		case m.managementCluster != "" && m.MCHealth.StatusError != nil:
+			clusterName, health = m.managementCluster, m.MCHealth
+		case m.workloadCluster != "" && m.WCHealth.StatusError != nil:
+			clusterName, health = m.workloadCluster, m.WCHealth
+		default:
+			return []string{"Nothing has failed: no failed port-forwards and no failing cluster health checks."}
+		}
+		lines = append(lines, fmt.Sprintf("Most recent failure: cluster %s at %s: %v", clusterName, health.LastUpdated.Format("15:04:05"), health.StatusError))
+	}
+
+	// The cluster the failure depends on
+	switch {
+	case health.StatusError != nil:
+		if failed != nil {
+			lines = append(lines, fmt.Sprintf("Its cluster %s is failing its health check: %v", clusterName, health.StatusError))
+		}
+		evidence = append(evidence, health.StatusError.Error())
+	case health.ReadyNodes < health.TotalNodes:
+		lines = append(lines, fmt.Sprintf("Its cluster %s has only %d/%d nodes ready.", clusterName, health.ReadyNodes, health.TotalNodes))
+	case !health.LastUpdated.IsZero():
+		lines = append(lines, fmt.Sprintf("Its cluster %s is healthy (%d/%d nodes), so the problem is specific to the port-forward.", clusterName, health.ReadyNodes, health.TotalNodes))
+	}
+	if failed != nil && len(failed.history) > 0 {
+		lines = append(lines, "Recent state transitions:")
+		start := len(failed.history) - 3
+		if start < 0 {
+			start = 0
+		}
+		for _, t := range failed.history[start:] {
+			lines = append(lines, "  "+t.String())
+		}
+	}
+
+	causes := matchFailureSignatures(evidence)
+	if len(causes) == 0 {
+		return append(lines, "No known failure signature matched; see 'x' on the panel and the full log ('L').")
+	}
+	lines = append(lines, "Likely causes:")
+	for i, c := range causes {
+		lines = append(lines, fmt.Sprintf("  %d. %s: %s (seen: %q)", i+1, c.signature.cause, c.signature.fix, truncateEvidence(c.evidence)))
+	}
+	return lines
+}
+
+// matchFailureSignatures matches lines against failureSignatures and returns the matching ones, most likely first.
+func matchFailureSignatures(lines []string) []likelyCause {
+	var causes []likelyCause
+	for _, sig := range failureSignatures {
+		c := likelyCause{signature: sig}
+		for _, line := range lines {
+			lower := strings.ToLower(line)
+			for _, p := range sig.patterns {
+				if strings.Contains(lower, p) {
+					if c.score == 0 {
+						c.evidence = line
+					}
+					c.score++
+					break
+				}
+			}
+		}
+		if c.score > 0 {
+			c.score += sig.weight
+			causes = append(causes, c)
+		}
+	}
+	sort.SliceStable(causes, func(i, j int) bool { return causes[i].score > causes[j].score })
+	return causes
+}
+
+// truncateEvidence shortens a matched line for display.
+func truncateEvidence(line string) string {
+	const maxLen = 80
+	if len(line) <= maxLen {
+		return line
+	}
+	return line[:maxLen-3] + "..."
+}
@@ -0,0 +1,58 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTriageLastFailure(t *testing.T) {
+	now := time.Now()
+	m := model{
+		managementCluster: "alpha",
+		MCHealth:          clusterHealthInfo{ReadyNodes: 3, TotalNodes: 3, LastUpdated: now},
+		combinedOutput:    newLogBuffer(100, 1<<20),
+		portForwardOrder:  []string{"Prometheus (MC)", "Grafana (MC)"},
+		portForwards: map[string]*portForwardProcess{
+			"Prometheus (MC)": {label: "Prometheus (MC)", lastError: "dial tcp: i/o timeout", lastErrorAt: now.Add(-time.Hour)},
+			"Grafana (MC)":    {label: "Grafana (MC)", lastError: "Forwarding failed: connection reset", lastErrorAt: now.Add(-time.Minute)},
+		},
+	}
+	m.combinedOutput.Append(
+		"[Grafana (MC)] unable to listen on port 3000: listen tcp4 127.0.0.1:3000: bind: address already in use",
+		"[Prometheus (MC)] certificate has expired",
+	)
+
+	report := strings.Join(triageLastFailure(m), "\n")
+	if !strings.Contains(report, "Most recent failure: Grafana (MC)") {
+		t.Errorf("expected Grafana to be triaged:\n%s", report)
+	}
+	if !strings.Contains(report, "1. the local port is used by another process") {
+		t.Errorf("expected port in use to rank first:\n%s", report)
+	}
+	if !strings.Contains(report, "2. the cluster API is unreachable") {
+		t.Errorf("expected unreachable cluster to rank second:\n%s", report)
+	}
+	if strings.Contains(report, "Teleport credentials") {
+		t.Errorf("log lines of other port-forwards must not be used:\n%s", report)
+	}
+}
+
+func TestTriageFailingCluster(t *testing.T) {
+	m := model{
+		managementCluster: "alpha",
+		MCHealth:          clusterHealthInfo{StatusError: errors.New("x509: certificate has expired or is not yet valid"), LastUpdated: time.Now()},
+		combinedOutput:    newLogBuffer(100, 1<<20),
+		portForwards:      map[string]*portForwardProcess{},
+	}
+	report := strings.Join(triageLastFailure(m), "\n")
+	if !strings.Contains(report, "cluster alpha") || !strings.Contains(report, "1. Teleport credentials") {
+		t.Errorf("unexpected report:\n%s", report)
+	}
+
+	m.MCHealth.StatusError = nil
+	if got := triageLastFailure(m); len(got) != 1 || !strings.HasPrefix(got[0], "Nothing has failed") {
+		t.Errorf("got %v", got)
+	}
+}
@@ -21,19 +21,30 @@ type clusterHealthInfo struct {
 // It is designed for use with client-go based port forwarding and holds necessary details
 // like the target service, ports, Kubernetes context, and its current operational status.
 type portForwardProcess struct {
-	label                 string        // User-friendly label for the port-forward (e.g., "Prometheus (MC)").
-	pid                   int           // PID of the process, mainly for informational/logging purposes if available (less critical with client-go).
-	stopChan              chan struct{} // Channel used to signal the port-forwarding goroutine to stop.
-	output                []string      // Stores general output or log messages specific to this port-forward.
-	err                   error         // Any error encountered by this port-forwarding process.
-	port                  string        // Port mapping string (e.g., "8080:8080").
-	isWC                  bool          // True if this port-forward targets a workload cluster service.
-	context               string        // The Kubernetes context name this port-forward targets.
-	namespace             string        // Kubernetes namespace of the target service.
-	service               string        // Name of the Kubernetes service to port-forward to.
-	active                bool          // Whether this port-forward is configured to be active (i.e., should be running).
-	statusMsg             string        // Detailed status message for display in the TUI (e.g., "Running", "Error").
-	forwardingEstablished bool          // True if the client-go port-forwarder has successfully established the connection.
+	label                 string               // User-friendly label for the port-forward (e.g., "Prometheus (MC)").
+	pid                   int                  // PID of the process, mainly for informational/logging purposes if available (less critical with client-go).
+	stopChan              chan struct{}        // Channel used to signal the port-forwarding goroutine to stop.
+	output                []string             // Stores general output or log messages specific to this port-forward.
+	err                   error                // Any error encountered by this port-forwarding process.
+	port                  string               // Port mapping string (e.g., "8080:8080").
+	isWC                  bool                 // True if this port-forward targets a workload cluster service.
+	context               string               // The Kubernetes context name this port-forward targets.
+	namespace             string               // Kubernetes namespace of the target service.
+	service               string               // Name of the Kubernetes service to port-forward to.
+	active                bool                 // Whether this port-forward is configured to be active (i.e., should be running).
+	statusMsg             string               // Detailed status message for display in the TUI (e.g., "Running", "Error").
+	forwardingEstablished bool                 // True if the client-go port-forwarder has successfully established the connection.
+	correlationID         string               // ID of the operation that last (re)started this port-forward, used to tag its log lines.
+	lastError             string               // Error of the current failure; cleared once the port-forward is set up or restarted again.
+	lastErrorAt           time.Time            // When lastError was reported.
+	history               []stateTransition    // Bounded history of state transitions, oldest first.
+	ephemeral             bool                 // True if created ad hoc with 'f' rather than configured for the connection.
+	expiresAt             time.Time            // When an ephemeral port-forward is removed; zero if it has no TTL.
+	runningSince          time.Time            // When the port-forward last became Running; zero while it is not running.
+	restarts              map[restartCause]int // Restarts since the connection was made, by cause.
+	reconnectAttempts     int                  // Automatic reconnects since the port-forward was last ready or restarted by hand.
+	reconnectToken        int                  // Identifies the scheduled reconnect; bumped to cancel it.
+	reconnectPending      bool                 // True while an automatic reconnect is scheduled.
 }
 
 // Define messages for Bubble Tea
@@ -124,8 +135,9 @@ type startNewConnectionInputMsg struct{}
 // submitNewConnectionMsg carries the management and workload cluster names entered by the user
 // to initiate a new connection sequence.
 type submitNewConnectionMsg struct {
-	mc string // Management Cluster name.
-	wc string // Workload Cluster name (optional).
+	mc            string // Management Cluster name.
+	wc            string // Workload Cluster name (optional).
+	correlationID string // ID of the connection operation, propagated through login, context switch and port-forward restarts.
 }
 
 // cancelNewConnectionInputMsg signals the TUI to exit the new connection input mode and revert.
@@ -144,6 +156,7 @@ type kubeLoginResultMsg struct {
 	desiredWcShortName string // If MC login was successful, this carries the WC name for the next step.
 	loginStdout        string // Captured stdout from the `tsh kube login` command.
 	loginStderr        string // Captured stderr from the `tsh kube login` command.
+	correlationID      string // ID of the connection operation this login belongs to.
 	err                error  // Error encountered during the login attempt, if any.
 }
 
@@ -154,18 +167,22 @@ type contextSwitchAndReinitializeResultMsg struct {
 	desiredMcName   string // The Management Cluster name targeted by the user.
 	desiredWcName   string // The Workload Cluster name targeted by the user.
 	diagnosticLog   string // A log of actions taken during the connection attempt.
+	correlationID   string // ID of the connection operation this result belongs to.
 	err             error  // Any error that prevented successful connection and re-initialization.
 }
 
 // kubeContextSwitchedMsg indicates the result of an explicit attempt to switch the Kubernetes context.
 type kubeContextSwitchedMsg struct {
 	TargetContext string // The Kubernetes context that was the target of the switch attempt.
+	correlationID string // ID of the context switch operation.
 	err           error  // Error encountered during the context switch, if any.
 }
 
 // clusterListResultMsg carries the list of available management and workload clusters,
 // typically fetched for autocompletion purposes.
 type clusterListResultMsg struct {
-	info *utils.ClusterInfo // Pointer to the struct containing cluster lists.
-	err  error              // Error encountered while fetching the cluster list, if any.
+	info         *utils.ClusterInfo   // Pointer to the struct containing cluster lists.
+	kubeContexts []string             // Contexts present in the kubeconfig, used to show login state (best effort).
+	recent       map[string]time.Time // Last time each cluster was connected to (best effort).
+	err          error                // Error encountered while fetching the cluster list, if any.
 }
@@ -3,6 +3,9 @@ package tui
 import (
 	"fmt"
 	"strings"
+	"time"
+
+	"github.com/giantswarm/envctl/internal/utils"
 
 	// For time.Format
 	"github.com/charmbracelet/lipgloss"
@@ -47,11 +50,14 @@ func renderMcPane(m model, paneWidth int) string {
 		healthStyle = healthLoadingStyle
 	} else if m.MCHealth.StatusError != nil {
 		healthStatusText = fmt.Sprintf("Nodes: Error (%s)", m.MCHealth.LastUpdated.Format("15:04:05"))
+		if utils.IsProxyError(m.MCHealth.StatusError) {
+			healthStatusText = fmt.Sprintf("Nodes: Proxy Error (%s)", m.MCHealth.LastUpdated.Format("15:04:05"))
+		}
 		healthStyle = healthErrorStyle
 	} else {
 		healthStatusText = fmt.Sprintf("Nodes: %d/%d", m.MCHealth.ReadyNodes, m.MCHealth.TotalNodes)
 		if m.MCHealth.ReadyNodes < m.MCHealth.TotalNodes {
-			healthStatusText = "[WARN] " + healthStatusText
+			healthStatusText = "[DEGRADED] " + healthStatusText
 			healthStyle = healthWarnStyle
 		} else {
 			healthStyle = healthGoodStyle
@@ -121,11 +127,14 @@ func renderWcPane(m model, paneWidth int) string {
 		healthStyle = healthLoadingStyle
 	} else if m.WCHealth.StatusError != nil {
 		healthStatusText = fmt.Sprintf("Nodes: Error")
+		if utils.IsProxyError(m.WCHealth.StatusError) {
+			healthStatusText = "Nodes: Proxy Error"
+		}
 		healthStyle = healthErrorStyle
 	} else {
 		healthStatusText = fmt.Sprintf("Nodes: %d/%d", m.WCHealth.ReadyNodes, m.WCHealth.TotalNodes)
 		if m.WCHealth.ReadyNodes < m.WCHealth.TotalNodes {
-			healthStatusText = "[WARN] " + healthStatusText
+			healthStatusText = "[DEGRADED] " + healthStatusText
 			healthStyle = healthWarnStyle
 		} else {
 			healthStyle = healthGoodStyle
@@ -179,10 +188,14 @@ func renderPortForwardPanel(pf *portForwardProcess, m model, targetOuterWidth in
 	// Selects base and focused styles (border, background) according to the port forward's current state (error, running, exited, initializing).
 	var baseStyleForPanel, focusedBaseStyleForPanel lipgloss.Style
 	statusToCheck := strings.ToLower(pf.statusMsg)
+	healthState, degradedReason := portForwardHealth(m, pf)
 
 	if pf.err != nil || strings.HasPrefix(statusToCheck, "failed") || strings.HasPrefix(statusToCheck, "error") || strings.HasPrefix(statusToCheck, "restart failed") {
 		baseStyleForPanel = panelStatusErrorStyle
 		focusedBaseStyleForPanel = focusedPanelStatusErrorStyle
+	} else if healthState == pfStateDegraded {
+		baseStyleForPanel = panelStatusDegradedStyle
+		focusedBaseStyleForPanel = focusedPanelStatusDegradedStyle
 	} else if pf.forwardingEstablished {
 		baseStyleForPanel = panelStatusRunningStyle
 		focusedBaseStyleForPanel = focusedPanelStatusRunningStyle
@@ -207,6 +220,8 @@ func renderPortForwardPanel(pf *portForwardProcess, m model, targetOuterWidth in
 	var contentFgTextStyle lipgloss.Style
 	if pf.err != nil || strings.HasPrefix(statusToCheck, "failed") || strings.HasPrefix(statusToCheck, "error") || strings.HasPrefix(statusToCheck, "restart failed") {
 		contentFgTextStyle = statusMsgErrorStyle
+	} else if healthState == pfStateDegraded {
+		contentFgTextStyle = statusMsgDegradedStyle
 	} else if pf.forwardingEstablished {
 		contentFgTextStyle = statusMsgRunningStyle
 	} else if strings.HasPrefix(statusToCheck, "exited") || strings.HasPrefix(statusToCheck, "killed") {
@@ -241,6 +256,10 @@ func renderPortForwardPanel(pf *portForwardProcess, m model, targetOuterWidth in
 	pfContentBuilder.WriteString(contentFgTextStyle.Render(
 		fmt.Sprintf("Status: %s", trimStatusMessage(pf.statusMsg)),
 	))
+	if degradedReason != "" {
+		pfContentBuilder.WriteString("\n")
+		pfContentBuilder.WriteString(contentFgTextStyle.Render("Degraded: " + degradedReason))
+	}
 
 	textForPanel := pfContentBuilder.String()
 
@@ -313,11 +332,9 @@ func renderCombinedLogPanel(m *model, availableWidth int, logSectionHeight int)
 
 	// Create a panel with specific styling
 	// Make sure we apply NO height limit to the panel
-	basePanel := panelStatusDefaultStyle.Copy().
+	basePanel := logPanelStyle.Copy().
 		Width(innerWidth).
-		MaxHeight(0). // No max height limit!
-		BorderForeground(lipgloss.AdaptiveColor{Light: "#606060", Dark: "#A0A0A0"}).
-		Background(lipgloss.AdaptiveColor{Light: "#F8F8F8", Dark: "#2A2A3A"})
+		MaxHeight(0) // No max height limit!
 
 	// Render the panel with our content inside
 	renderedPanel := basePanel.Render(panelContent)
@@ -328,19 +345,6 @@ func renderCombinedLogPanel(m *model, availableWidth int, logSectionHeight int)
 
 	// If we're short on height or width, add padding
 	if actualHeight < logSectionHeight || actualWidth < availableWidth {
-		// Log discrepancies if in debug mode
-		if m.debugMode {
-			if actualHeight < logSectionHeight {
-				heightDiffMsg := fmt.Sprintf("[Panel height: %d/%d]", actualHeight, logSectionHeight)
-				m.combinedOutput = append([]string{heightDiffMsg}, m.combinedOutput...)
-			}
-
-			if actualWidth < availableWidth {
-				widthDiffMsg := fmt.Sprintf("[Panel width: %d/%d]", actualWidth, availableWidth)
-				m.combinedOutput = append([]string{widthDiffMsg}, m.combinedOutput...)
-			}
-		}
-
 		// Create final wrapped panel with exact dimensions
 		finalPanel := lipgloss.NewStyle().
 			Width(availableWidth).
@@ -378,6 +382,8 @@ func renderHelpOverlay(m model, width, height int) string {
 	helpContent.WriteString("\n")
 	helpContent.WriteString(formatShortcut("Shift+Tab", "Previous panel"))
 	helpContent.WriteString("\n")
+	helpContent.WriteString(formatShortcut("[ / ]", "Previous/next page of port forwards"))
+	helpContent.WriteString("\n")
 
 	// Operations section
 	helpContent.WriteString(helpSectionStyle.Render("Operations"))
@@ -386,8 +392,27 @@ func renderHelpOverlay(m model, width, height int) string {
 	helpContent.WriteString("\n")
 	helpContent.WriteString(formatShortcut("r", "Restart port forwarding for focused panel"))
 	helpContent.WriteString("\n")
+	helpContent.WriteString(formatShortcut("R", "Restart focused cluster and its port forwards"))
+	helpContent.WriteString("\n")
+	helpContent.WriteString(formatShortcut("P", "Pause/resume health checks"))
+	helpContent.WriteString("\n")
+	helpContent.WriteString(formatShortcut("m / M", "Maintenance of focused panel / everything"))
+	helpContent.WriteString("\n")
+	helpContent.WriteString(formatShortcut("T", "Port forward table (sort, filter, bulk restart/stop)"))
+	helpContent.WriteString("\n")
 	helpContent.WriteString(formatShortcut("s", "Switch Kubernetes context"))
 	helpContent.WriteString("\n")
+	helpContent.WriteString(formatShortcut("x", "Explain state of focused panel"))
+	helpContent.WriteString(formatShortcut("X", "Triage most recent failure"))
+	helpContent.WriteString("\n")
+	helpContent.WriteString(formatShortcut("p", "Pick a cluster to connect to"))
+	helpContent.WriteString("\n")
+	helpContent.WriteString(formatShortcut("f", "Create an ephemeral port forward"))
+	helpContent.WriteString("\n")
+	helpContent.WriteString(formatShortcut("d", "Remove focused ephemeral port forward"))
+	helpContent.WriteString("\n")
+	helpContent.WriteString(formatShortcut(":", "Run kubectl against focused cluster (if enabled)"))
+	helpContent.WriteString("\n")
 	helpContent.WriteString(formatShortcut("N", "Start new connection"))
 	helpContent.WriteString("\n")
 
@@ -422,9 +447,177 @@ func renderHelpOverlay(m model, width, height int) string {
 		Render(helpContent.String())
 }
 
+// renderClusterPickerOverlay renders the cluster picker: a filter line and the clusters grouped by installation,
+// each with its login state and when it was last used. Only the rows around the highlighted entry are shown
+// if the list does not fit the screen.
+func renderClusterPickerOverlay(m model, width, height int) string {
+	var b strings.Builder
+	b.WriteString(helpTitleStyle.Render("Connect to Cluster"))
+	b.WriteString("\n")
+	b.WriteString(fmt.Sprintf("Filter: %s_\n\n", m.picker.query))
+
+	entries := pickerEntries(m)
+	if m.clusterInfo == nil {
+		b.WriteString("Loading clusters...\n")
+	} else if len(entries) == 0 {
+		b.WriteString("No clusters match the filter.\n")
+	}
+
+	// Keep the highlighted row visible
+	maxRows := height - 14
+	if maxRows < 3 {
+		maxRows = 3
+	}
+	start := 0
+	if m.picker.cursor >= maxRows {
+		start = m.picker.cursor - maxRows + 1
+	}
+	end := start + maxRows
+	if end > len(entries) {
+		end = len(entries)
+	}
+
+	for i := start; i < end; i++ {
+		e := entries[i]
+		cursor := "  "
+		if i == m.picker.cursor {
+			cursor = "> "
+		}
+		login := loggedOutIcon
+		if e.loggedIn {
+			login = loggedInIcon
+		}
+		name := e.mc
+		if e.wc != "" {
+			name = "    " + e.wc
+		}
+		lastUsed := "never used"
+		if !e.lastUsed.IsZero() {
+			lastUsed = "used " + formatAge(time.Since(e.lastUsed)) + " ago"
+		}
+		line := fmt.Sprintf("%s%s %-30s %s", cursor, login, name, lastUsed)
+		if i == m.picker.cursor {
+			line = helpKeyStyle.Render(line)
+		}
+		b.WriteString(line + "\n")
+	}
+
+	b.WriteString(fmt.Sprintf("\n%s logged in  %s not logged in\n", loggedInIcon, loggedOutIcon))
+	b.WriteString("Type to filter, ↑/↓ select, Enter connect (WC row = MC+WC), Esc close")
+
+	overlayWidth := width * 2 / 3
+	if overlayWidth > 90 {
+		overlayWidth = 90
+	} else if overlayWidth < 50 {
+		overlayWidth = 50
+	}
+	contentWidth := overlayWidth - helpOverlayStyle.GetHorizontalFrameSize()
+	if contentWidth < 0 {
+		contentWidth = 0
+	}
+	return helpOverlayStyle.Copy().Width(contentWidth).Render(b.String())
+}
+
+// renderKubectlPaneOverlay renders the kubectl pane: a title naming the target context,
+// the scrollback of previous commands and the command input.
+func renderKubectlPaneOverlay(m model, width, height int) string {
+	contentWidth := width - logOverlayStyle.GetHorizontalFrameSize()
+	if contentWidth < 0 {
+		contentWidth = 0
+	}
+	title := helpTitleStyle.Render(fmt.Sprintf("kubectl (context: %s)", kubectlContextForFocus(m)))
+	status := "Enter run, ↑/↓ history, PgUp/PgDn scroll, Esc close"
+	if m.kubectl.running {
+		status = "Running..."
+	}
+
+	body := lipgloss.JoinVertical(lipgloss.Left,
+		title,
+		m.kubectl.viewport.View(),
+		m.kubectl.input.View(),
+		"",
+		status,
+	)
+	return logOverlayStyle.Copy().
+		Width(contentWidth).
+		Height(height - logOverlayStyle.GetVerticalFrameSize()).
+		Render(body)
+}
+
+// renderRestartTreeConfirmOverlay renders the plan of a restart tree awaiting confirmation.
+func renderRestartTreeConfirmOverlay(m model, width int) string {
+	contentWidth := int(float64(width)*0.7) - helpOverlayStyle.GetHorizontalFrameSize()
+	if contentWidth < 0 {
+		contentWidth = 0
+	}
+	lines := []string{helpTitleStyle.Render(fmt.Sprintf("Restart %s and its dependents", m.restartTree.cluster))}
+	for i, action := range m.restartTree.plan {
+		lines = append(lines, fmt.Sprintf("%d. %s", i+1, action))
+	}
+	lines = append(lines, "", "Enter/y restart, Esc cancel")
+	return helpOverlayStyle.Copy().Width(contentWidth).Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+}
+
+// renderEphemeralFormOverlay renders the form creating an ephemeral port-forward:
+// the target context, the input, the expected syntax and the last validation error.
+func renderEphemeralFormOverlay(m model, width int) string {
+	contentWidth := int(float64(width)*0.7) - helpOverlayStyle.GetHorizontalFrameSize()
+	if contentWidth < 0 {
+		contentWidth = 0
+	}
+	lines := []string{
+		helpTitleStyle.Render(fmt.Sprintf("Ephemeral port-forward (context: %s)", kubectlContextForFocus(m))),
+		m.ephemeral.input.View(),
+		"",
+		"<namespace>/[<kind>/]<name> <local>[:<remote>] [<ttl>]",
+		"Kind defaults to service. Without a TTL it runs until the connection changes.",
+		"Enter create, Esc cancel",
+	}
+	if m.ephemeral.err != "" {
+		lines = append(lines, "", healthErrorStyle.Render("Error: "+m.ephemeral.err))
+	}
+	return helpOverlayStyle.Copy().Width(contentWidth).Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+}
+
+// formatAge formats a duration coarsely for display, e.g. "45s", "12m", "3h" or "2d".
+func formatAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
+
 // renderNewConnectionInputView renders the UI when the application is in new connection input mode.
 func renderNewConnectionInputView(m model, width int) string {
 	var inputPrompt strings.Builder
+	if m.currentInputStep == confirmInputStep {
+		// Show what the switch will do before anything is changed
+		target := m.pendingConnection.mc
+		if m.pendingConnection.wc != "" {
+			target += " / " + m.pendingConnection.wc
+		}
+		inputPrompt.WriteString(fmt.Sprintf("Switching to %s will:\n\n", target))
+		for _, action := range m.pendingImpact {
+			inputPrompt.WriteString("• " + action.String() + "\n")
+		}
+		if len(m.unsatisfiable) > 0 {
+			inputPrompt.WriteString("\nNo longer available after the switch:\n")
+			for _, line := range m.unsatisfiable {
+				inputPrompt.WriteString("• " + line + "\n")
+			}
+		}
+		if downtime := expectedDowntime(m.pendingImpact); downtime > 0 {
+			inputPrompt.WriteString(fmt.Sprintf("\nPort-forwards are expected to be ready about %s after login (from earlier starts).\n", formatAge(downtime)))
+		}
+		inputPrompt.WriteString("\n[Enter/y to confirm, Esc to cancel]")
+		return newConnectionInputStyle.Copy().Width(width - 4).Render(inputPrompt.String())
+	}
 	inputPrompt.WriteString("Enter new cluster information (ESC to cancel, Enter to confirm/next)\n\n")
 	inputPrompt.WriteString(m.newConnectionInput.View()) // Renders the text input bubble
 	if m.currentInputStep == mcInputStep {
@@ -432,8 +625,7 @@ func renderNewConnectionInputView(m model, width int) string {
 	} else {
 		inputPrompt.WriteString(fmt.Sprintf("\n\n[Input: Workload Cluster Name for MC: %s (optional)]", m.stashedMcName))
 	}
-	inputViewStyle := lipgloss.NewStyle().Padding(1, 2).Border(lipgloss.RoundedBorder()).Width(width - 4).Align(lipgloss.Center)
-	return inputViewStyle.Render(inputPrompt.String())
+	return newConnectionInputStyle.Copy().Width(width - 4).Render(inputPrompt.String())
 }
 
 // renderHeader renders the global header for the TUI.
@@ -450,9 +642,30 @@ func renderHeader(m model, contentWidth int) string {
 	// Regular header with more information
 	headerTitleString := "envctl TUI - Press h for Help | Tab to Navigate | q to Quit"
 
+	// Make firing alerts visible at a glance
+	if n := len(m.alerts); n > 0 {
+		headerTitleString += fmt.Sprintf(" | ALERTS: %d", n)
+	}
+
+	// Make a health check pause visible, since failures are not detected while it lasts
+	if healthChecksPaused(m, time.Now()) {
+		headerTitleString += " | HEALTH CHECKS PAUSED until " + m.healthPausedUntil.Format("15:04")
+	}
+
+	// Make maintenance visible, since failures are not recovered automatically while it lasts
+	if windows := renderMaintenance(m, time.Now()); windows != "" {
+		headerTitleString += " | MAINTENANCE: " + windows
+	}
+
+	// Add the environment health rollup and trend once samples exist
+	if rollup := renderHealthRollup(m); rollup != "" {
+		headerTitleString += " | " + rollup
+	}
+
 	// Add color mode debug info if debugMode is enabled
 	if m.debugMode {
 		headerTitleString += fmt.Sprintf(" | Mode: %s | Toggle Dark: D | Debug: z", m.colorMode)
+		headerTitleString += fmt.Sprintf(" | Events: %d in %d batches, %d dropped", m.events.coalesced, m.events.batches, m.events.dropped)
 	}
 
 	// Make sure we leave enough space for the header content by not over-subtracting frame size
@@ -465,9 +678,18 @@ func renderHeader(m model, contentWidth int) string {
 	}
 
 	// Otherwise use styled header with full content
-	return headerStyle.Copy().
+	header := headerStyle.Copy().
 		Width(headerWidth - frameSize).
 		Render(headerTitleString)
+
+	// Make read-only mode impossible to miss
+	if m.readOnly {
+		banner := readOnlyBannerStyle.Copy().
+			Width(headerWidth - readOnlyBannerStyle.GetHorizontalFrameSize()).
+			Render("READ-ONLY: " + m.readOnlyReason)
+		header = lipgloss.JoinVertical(lipgloss.Left, header, banner)
+	}
+	return header
 }
 
 // renderContextPanesRow renders the row containing MC and WC info panes.
@@ -493,12 +715,12 @@ func renderContextPanesRow(m model, contentWidth int, maxRowHeight int) string {
 		mcPaneWidth := mcInnerWidth + mcBorderSize
 		wcPaneWidth := wcInnerWidth + wcBorderSize
 
-		renderedMcPane := renderMcPane(m, mcPaneWidth)
-		renderedWcPane := renderWcPane(m, wcPaneWidth)
+		renderedMcPane := m.render.panel("mc", contextPaneKey(m, mcPaneWidth, m.MCHealth), func() string { return renderMcPane(m, mcPaneWidth) })
+		renderedWcPane := m.render.panel("wc", contextPaneKey(m, wcPaneWidth, m.WCHealth), func() string { return renderWcPane(m, wcPaneWidth) })
 		rowView = lipgloss.JoinHorizontal(lipgloss.Top, renderedMcPane, renderedWcPane)
 	} else {
 		// If only MC pane, it should take full width
-		rowView = renderMcPane(m, contentWidth)
+		rowView = m.render.panel("mc", contextPaneKey(m, contentWidth, m.MCHealth), func() string { return renderMcPane(m, contentWidth) })
 	}
 
 	// Ensure rowView itself is exactly contentWidth wide, aligning its internal content left.
@@ -509,18 +731,42 @@ func renderContextPanesRow(m model, contentWidth int, maxRowHeight int) string {
 		Render(rowView)
 }
 
-// renderPortForwardingRow renders the row containing port forwarding panels.
+// renderPortForwardingRow renders the port forwarding panels as a grid sized to the terminal (see portForwardGrid).
+// If not all panels fit, only the page containing the focused panel is shown, followed by a page indicator
+// that also summarizes problems in the hidden panels.
 func renderPortForwardingRow(m model, contentWidth int, maxRowHeight int) string {
-	numFixedColumns := 3 // From original View logic for port forward panels
-
-	// Get panels to show
-	pfPanelKeysToShow := []string{}
-	for _, key := range m.portForwardOrder {
-		if key != mcPaneFocusKey && key != wcPaneFocusKey { // mcPaneFocusKey and wcPaneFocusKey are constants
-			pfPanelKeysToShow = append(pfPanelKeysToShow, key)
+	pfPanelKeys := portForwardPanelKeys(m)
+	columns, rows := portForwardGrid(len(pfPanelKeys), contentWidth, maxRowHeight)
+	pageSize := columns * rows
+	first, last := portForwardPageBounds(m, pfPanelKeys, pageSize)
+
+	var rowViews []string
+	for rowStart := first; rowStart < last || len(rowViews) == 0; rowStart += columns {
+		rowEnd := rowStart + columns
+		if rowEnd > last {
+			rowEnd = last
 		}
+		rowViews = append(rowViews, renderPortForwardPanelRow(m, pfPanelKeys[rowStart:rowEnd], columns, contentWidth))
+	}
+	if len(pfPanelKeys) > pageSize {
+		rowViews = append(rowViews, renderPortForwardPageIndicator(m, pfPanelKeys, first, last))
 	}
 
+	// Ensure the grid is exactly contentWidth wide
+	return lipgloss.NewStyle().
+		Width(contentWidth).
+		Align(lipgloss.Left).
+		MaxHeight(maxRowHeight).
+		Render(lipgloss.JoinVertical(lipgloss.Left, rowViews...))
+}
+
+// renderPortForwardPanelRow renders one row of port forwarding panels, filling unused columns with empty placeholders.
+// - m: The current TUI model.
+// - pfPanelKeysToShow: The labels of the port forwards in this row, at most numFixedColumns.
+// - numFixedColumns: The number of columns of the grid.
+// - contentWidth: The exact width of the row.
+func renderPortForwardPanelRow(m model, pfPanelKeysToShow []string, numFixedColumns int, contentWidth int) string {
+
 	// Calculate total border size for all panels
 	totalBorderSize := 0
 	for i := 0; i < numFixedColumns; i++ {
@@ -593,7 +839,9 @@ func renderPortForwardingRow(m model, contentWidth int, maxRowHeight int) string
 		if i < len(pfPanelKeysToShow) {
 			pfKey := pfPanelKeysToShow[i]
 			pf := m.portForwards[pfKey]
-			renderedPfCell := renderPortForwardPanel(pf, m, currentPanelWidth)
+			renderedPfCell := m.render.panel("pf/"+pf.label, portForwardPanelKey(m, pf, currentPanelWidth), func() string {
+				return renderPortForwardPanel(pf, m, currentPanelWidth)
+			})
 			cellsRendered[i] = renderedPfCell
 		} else {
 			// Render an empty placeholder panel with exact width
@@ -602,12 +850,5 @@ func renderPortForwardingRow(m model, contentWidth int, maxRowHeight int) string
 		}
 	}
 
-	joinedPanels := lipgloss.JoinHorizontal(lipgloss.Top, cellsRendered...)
-
-	// Ensure row is exactly contentWidth wide
-	return lipgloss.NewStyle().
-		Width(contentWidth).
-		Align(lipgloss.Left).
-		MaxHeight(maxRowHeight).
-		Render(joinedPanels)
+	return lipgloss.JoinHorizontal(lipgloss.Top, cellsRendered...)
 }
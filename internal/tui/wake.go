@@ -0,0 +1,106 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/giantswarm/envctl/internal/utils"
+)
+
+const (
+	// wakeCheckInterval is how often the TUI checks for sleep/wake and network changes.
+	wakeCheckInterval = 5 * time.Second
+	// wakeGapThreshold is how much longer than wakeCheckInterval a tick may take before
+	// it is considered a resume from sleep (the process did not run in between).
+	wakeGapThreshold = 30 * time.Second
+)
+
+// wakeCheckMsg is sent periodically to detect sleep/wake cycles and network changes.
+type wakeCheckMsg struct {
+	at      time.Time // Wall-clock time of the tick.
+	network string    // Network fingerprint at the time of the tick (empty if it could not be determined).
+}
+
+// wakeCheckCmd schedules the next sleep/wake and network change check.
+func wakeCheckCmd() tea.Cmd {
+	return tea.Tick(wakeCheckInterval, func(t time.Time) tea.Msg {
+		network, _ := utils.NetworkFingerprint()
+		return wakeCheckMsg{at: t, network: network}
+	})
+}
+
+// handleWakeCheckMsg compares the tick with the previous one. A wall-clock gap much larger than the
+// tick interval means the machine was suspended; a different network fingerprint means the network
+// changed (new Wi-Fi, VPN up/down). Either way, connections are likely broken, so the TUI reconciles
+// immediately instead of waiting for the next health check to notice.
+func handleWakeCheckMsg(m model, msg wakeCheckMsg) (model, tea.Cmd) {
+	var reason string
+	// Round(0) strips the monotonic reading, which does not advance during suspend on all platforms.
+	if !m.lastWakeCheck.IsZero() {
+		if gap := msg.at.Round(0).Sub(m.lastWakeCheck.Round(0)); gap > wakeCheckInterval+wakeGapThreshold {
+			reason = fmt.Sprintf("resumed after sleep (%s without activity)", gap.Round(time.Second))
+		}
+	}
+	if reason == "" && m.networkFingerprint != "" && msg.network != "" && msg.network != m.networkFingerprint {
+		reason = "network change detected"
+	}
+	m.lastWakeCheck = msg.at
+	if msg.network != "" {
+		m.networkFingerprint = msg.network
+	}
+
+	if reason == "" || m.readOnly {
+		return m, wakeCheckCmd()
+	}
+	if healthChecksPaused(m, msg.at) {
+		m.combinedOutput.Append(fmt.Sprintf("[SYSTEM] %s; not reconciling while health checks are paused.", reason))
+		return m, wakeCheckCmd()
+	}
+	return reconcileAfterDisruption(m, reason, wakeCheckCmd())
+}
+
+// reconcileAfterDisruption re-checks cluster health (which also validates credentials) and restarts
+// all port-forwards after an event that most likely broke existing connections. Port-forwards under
+// maintenance are left alone; the cluster health checks still run.
+// - reason: Shown in the activity log and recorded in the port-forwards' state history.
+// - next: A command to run in addition, e.g. rescheduling the check that detected the disruption.
+func reconcileAfterDisruption(m model, reason string, next tea.Cmd) (model, tea.Cmd) {
+	correlationID := newCorrelationID()
+	m.combinedOutput.Append(fmt.Sprintf("[SYSTEM] %s%s: re-checking clusters and restarting port-forwards.", correlationTag(correlationID), reason))
+
+	cmds := []tea.Cmd{next}
+	if m.managementCluster != "" {
+		if mcIdentifier := m.getManagementClusterContextIdentifier(); mcIdentifier != "" {
+			m.MCHealth.IsLoading = true
+			cmds = append(cmds, fetchNodeStatusCmd(mcIdentifier, true, m.managementCluster))
+		}
+	}
+	if m.workloadCluster != "" {
+		if wcIdentifier := m.getWorkloadClusterContextIdentifier(); wcIdentifier != "" {
+			m.WCHealth.IsLoading = true
+			cmds = append(cmds, fetchNodeStatusCmd(wcIdentifier, false, m.workloadCluster))
+		}
+	}
+
+	n, skipped := 0, 0
+	for _, label := range m.portForwardOrder {
+		pf, ok := m.portForwards[label]
+		if !ok || !(pf.active || pf.stopChan != nil) {
+			continue
+		}
+		if portForwardInMaintenance(m, pf, time.Now()) {
+			skipped++
+			continue
+		}
+		n++
+		if cmd := restartPortForward(&m, pf, restartHealth, childCorrelationID(correlationID, n), reason); cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+	}
+	if skipped > 0 {
+		m.combinedOutput.Append(fmt.Sprintf("[SYSTEM] %sNot restarting %d port-forward(s) under maintenance.", correlationTag(correlationID), skipped))
+	}
+	return m, tea.Batch(cmds...)
+}
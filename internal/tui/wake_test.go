@@ -0,0 +1,31 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHandleWakeCheckMsgDetectsSleepAndNetworkChange(t *testing.T) {
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	m := model{combinedOutput: newLogBuffer(0, 0)}
+
+	// First tick only records the baseline.
+	m, _ = handleWakeCheckMsg(m, wakeCheckMsg{at: start, network: "en0=10.0.0.2/24"})
+	m, _ = handleWakeCheckMsg(m, wakeCheckMsg{at: start.Add(wakeCheckInterval), network: "en0=10.0.0.2/24"})
+	if m.combinedOutput.Len() != 0 {
+		t.Fatalf("expected no reconciliation for regular ticks, got %v", m.combinedOutput.Lines())
+	}
+
+	// A long gap means the machine was asleep.
+	m, _ = handleWakeCheckMsg(m, wakeCheckMsg{at: start.Add(10 * time.Minute), network: "en0=10.0.0.2/24"})
+	if m.combinedOutput.Len() != 1 || !strings.Contains(m.combinedOutput.Last(), "resumed after sleep") {
+		t.Fatalf("expected sleep to be detected, got %v", m.combinedOutput.Lines())
+	}
+
+	// A different network fingerprint means the network changed.
+	m, _ = handleWakeCheckMsg(m, wakeCheckMsg{at: start.Add(10*time.Minute + wakeCheckInterval), network: "utun0=100.64.0.1/32,en0=10.0.0.2/24"})
+	if m.combinedOutput.Len() != 2 || !strings.Contains(m.combinedOutput.Last(), "network change") {
+		t.Fatalf("expected network change to be detected, got %v", m.combinedOutput.Lines())
+	}
+}
@@ -0,0 +1,83 @@
+package utils
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+)
+
+// DefaultBindAddresses are the local addresses port-forwards listen on unless configured otherwise.
+var DefaultBindAddresses = []string{"127.0.0.1"}
+
+// bindAddresses maps a port-forward label or template name to the addresses it listens on;
+// the empty key holds the addresses for all other port-forwards. See SetBindAddresses.
+var bindAddresses = map[string][]string{"": DefaultBindAddresses}
+
+// SetBindAddresses configures the local addresses port-forwards listen on. It must be called before
+// any port-forward is started. Each entry is an address ("0.0.0.0", "::1", "localhost"), optionally
+// scoped to port-forwards as "NAME=ADDRESS", where NAME is a label ("Grafana (WC)") or a template
+// name ("Grafana"). Repeating an entry for the same scope adds addresses, e.g. "127.0.0.1" and "::1"
+// for dual-stack. Port-forwards without a scoped entry use the unscoped addresses, or
+// DefaultBindAddresses if there are none.
+// Returns an error if an address is not an IP address or "localhost".
+func SetBindAddresses(entries []string) error {
+	addresses := make(map[string][]string)
+	for _, entry := range entries {
+		scope, address := "", entry
+		if name, addr, ok := strings.Cut(entry, "="); ok {
+			scope, address = strings.TrimSpace(name), addr
+		}
+		address = strings.Trim(strings.TrimSpace(address), "[]")
+		if address != "localhost" && net.ParseIP(address) == nil {
+			return fmt.Errorf("invalid bind address %q, expected an IP address or localhost, optionally as NAME=ADDRESS", entry)
+		}
+		addresses[scope] = append(addresses[scope], address)
+	}
+	if _, ok := addresses[""]; !ok {
+		addresses[""] = DefaultBindAddresses
+	}
+	bindAddresses = addresses
+	return nil
+}
+
+// BindAddressesFor returns the local addresses the port-forward with the given label listens on.
+// An entry for the label takes precedence over one for its template name, e.g. "Grafana" for "Grafana (MC)".
+func BindAddressesFor(label string) []string {
+	if addresses, ok := bindAddresses[label]; ok {
+		return addresses
+	}
+	if name, _, ok := strings.Cut(label, " ("); ok {
+		if addresses, ok := bindAddresses[name]; ok {
+			return addresses
+		}
+	}
+	return bindAddresses[""]
+}
+
+// NonLoopbackBindAddresses returns the configured addresses that other hosts can connect to.
+// Port-forwards have no authentication, so listening on them exposes the cluster services.
+// The result is sorted, so warnings list the addresses in a stable order.
+func NonLoopbackBindAddresses() []string {
+	var exposed []string
+	seen := make(map[string]bool)
+	for _, addresses := range bindAddresses {
+		for _, address := range addresses {
+			if !IsLoopbackAddress(address) && !seen[address] {
+				seen[address] = true
+				exposed = append(exposed, address)
+			}
+		}
+	}
+	sort.Strings(exposed)
+	return exposed
+}
+
+// IsLoopbackAddress reports whether address only accepts connections from the local host.
+func IsLoopbackAddress(address string) bool {
+	if address == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(address)
+	return ip != nil && ip.IsLoopback()
+}
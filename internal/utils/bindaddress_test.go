@@ -0,0 +1,51 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBindAddresses(t *testing.T) {
+	defer func() { _ = SetBindAddresses(nil) }()
+
+	if got := BindAddressesFor("Grafana (MC)"); !reflect.DeepEqual(got, DefaultBindAddresses) {
+		t.Errorf("default: got %v", got)
+	}
+
+	if err := SetBindAddresses([]string{"127.0.0.1", "::1", "Grafana=0.0.0.0", "Grafana (WC)=[::1]"}); err != nil {
+		t.Fatal(err)
+	}
+	cases := map[string][]string{
+		"Prometheus (MC)": {"127.0.0.1", "::1"},
+		"Grafana (MC)":    {"0.0.0.0"},
+		"Grafana (WC)":    {"::1"},
+	}
+	for label, want := range cases {
+		if got := BindAddressesFor(label); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: got %v, want %v", label, got, want)
+		}
+	}
+	if got := NonLoopbackBindAddresses(); !reflect.DeepEqual(got, []string{"0.0.0.0"}) {
+		t.Errorf("non-loopback: got %v", got)
+	}
+
+	// Addresses from several scopes are listed once and in a stable order.
+	if err := SetBindAddresses([]string{"Loki=192.168.1.10", "0.0.0.0", "Grafana=10.0.0.5", "Grafana (WC)=0.0.0.0"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := NonLoopbackBindAddresses(); !reflect.DeepEqual(got, []string{"0.0.0.0", "10.0.0.5", "192.168.1.10"}) {
+		t.Errorf("non-loopback sorted: got %v", got)
+	}
+
+	// Only scoped entries: everything else keeps the default.
+	if err := SetBindAddresses([]string{"Grafana=localhost"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := BindAddressesFor("Prometheus (MC)"); !reflect.DeepEqual(got, DefaultBindAddresses) {
+		t.Errorf("scoped only: got %v", got)
+	}
+
+	if err := SetBindAddresses([]string{"Grafana=my-host"}); err == nil {
+		t.Error("expected an error for a host name")
+	}
+}
@@ -0,0 +1,118 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+)
+
+// DefaultChildEnvAllowlist lists the environment variables passed to child processes (tsh, kubectl) by default.
+// It covers what they need to find binaries, config and credentials, open a browser for SSO, run the
+// exec plugins of cloud provider contexts, reach clusters through a proxy and print in the user's locale. An entry ending in "*" matches every variable with that prefix.
+var DefaultChildEnvAllowlist = []string{
+	// Process basics
+	"PATH", "HOME", "USER", "LOGNAME", "SHELL", "TERM", "TMPDIR", "TMP", "TEMP", "LANG", "LC_*", "TZ", "XDG_*",
+	// Windows
+	"SYSTEMROOT", "SYSTEMDRIVE", "WINDIR", "COMSPEC", "PATHEXT", "USERPROFILE", "APPDATA", "LOCALAPPDATA", "PROGRAMDATA",
+	// Desktop session, so that tsh login can open the browser for SSO
+	"DISPLAY", "WAYLAND_DISPLAY", "BROWSER", "DBUS_SESSION_BUS_ADDRESS",
+	// Kubernetes and Teleport, including the tsh exec plugin kubectl runs for Teleport contexts
+	"KUBECONFIG", "KUBECACHEDIR", "TELEPORT_*", "TSH_*", "SSH_AUTH_SOCK",
+	// Exec plugins of cloud provider contexts (aws, gke-gcloud-auth-plugin), which only kubectl runs;
+	// tsh does not get these credentials
+	"kubectl:AWS_*", "kubectl:GOOGLE_APPLICATION_CREDENTIALS", "kubectl:CLOUDSDK_*",
+	// Proxies
+	"HTTP_PROXY", "HTTPS_PROXY", "NO_PROXY", "ALL_PROXY", "http_proxy", "https_proxy", "no_proxy", "all_proxy",
+}
+
+// ChildEnvPolicy controls which environment variables envctl passes to the processes it runs.
+// By default nothing outside the allowlist is passed, so tokens and secrets in envctl's environment
+// do not leak into tsh or kubectl.
+type ChildEnvPolicy struct {
+	// InheritAll passes envctl's complete environment, as older versions did. Allow is ignored; Set still applies.
+	InheritAll bool
+	// Allow lists the variables passed through: "NAME" or a "PREFIX*" pattern, optionally scoped to one
+	// command as "command:NAME" (e.g. "kubectl:AWS_*").
+	Allow []string
+	// Set injects variables as "NAME=VALUE", optionally scoped to one command as "command:NAME=VALUE".
+	Set []string
+}
+
+// childEnvPolicy is the policy applied by ChildEnv; see SetChildEnvPolicy.
+var childEnvPolicy = ChildEnvPolicy{Allow: DefaultChildEnvAllowlist}
+
+// SetChildEnvPolicy replaces the environment policy for child processes. It must be called before
+// any child process is started. The allowlist is used as given, so include DefaultChildEnvAllowlist to extend it.
+// Returns an error if a Set entry is not of the form NAME=VALUE.
+func SetChildEnvPolicy(policy ChildEnvPolicy) error {
+	for _, entry := range policy.Set {
+		_, assignment := splitEnvScope(entry)
+		if name, _, ok := strings.Cut(assignment, "="); !ok || name == "" {
+			return fmt.Errorf("invalid environment variable %q, expected NAME=VALUE or command:NAME=VALUE", entry)
+		}
+	}
+	childEnvPolicy = policy
+	return nil
+}
+
+// splitEnvScope splits a "command:entry" policy entry into its command and entry.
+// Entries without a scope apply to every command and return an empty command.
+func splitEnvScope(entry string) (command, rest string) {
+	colon := strings.Index(entry, ":")
+	// A colon after "=" belongs to the value, e.g. "NO_PROXY=localhost:8080".
+	if colon <= 0 || (strings.Contains(entry, "=") && colon > strings.Index(entry, "=")) {
+		return "", entry
+	}
+	return entry[:colon], entry[colon+1:]
+}
+
+// envNameMatches reports whether an environment variable name matches an allowlist pattern.
+// Names are compared case-insensitively on Windows, where the environment is case-insensitive.
+func envNameMatches(name, pattern string) bool {
+	if runtime.GOOS == "windows" {
+		name, pattern = strings.ToUpper(name), strings.ToUpper(pattern)
+	}
+	if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+		return strings.HasPrefix(name, prefix)
+	}
+	return name == pattern
+}
+
+// ChildEnv returns the environment for a child process according to the current policy, to be assigned
+// to exec.Cmd.Env.
+// - command: The program being run, e.g. "kubectl", used to apply command-scoped entries.
+func ChildEnv(command string) []string {
+	return filterEnv(os.Environ(), command, childEnvPolicy)
+}
+
+// filterEnv applies a policy to an environment in "NAME=VALUE" form.
+func filterEnv(environ []string, command string, policy ChildEnvPolicy) []string {
+	var allow []string
+	for _, entry := range policy.Allow {
+		if scope, pattern := splitEnvScope(entry); scope == "" || scope == command {
+			allow = append(allow, pattern)
+		}
+	}
+
+	var env []string
+	for _, kv := range environ {
+		name, _, _ := strings.Cut(kv, "=")
+		if policy.InheritAll {
+			env = append(env, kv)
+			continue
+		}
+		for _, pattern := range allow {
+			if envNameMatches(name, pattern) {
+				env = append(env, kv)
+				break
+			}
+		}
+	}
+	for _, entry := range policy.Set {
+		if scope, assignment := splitEnvScope(entry); scope == "" || scope == command {
+			env = append(env, assignment) // Later entries take precedence for exec.Cmd.
+		}
+	}
+	return env
+}
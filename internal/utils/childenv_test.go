@@ -0,0 +1,79 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFilterEnv(t *testing.T) {
+	environ := []string{
+		"PATH=/usr/bin",
+		"GITHUB_TOKEN=secret",
+		"TELEPORT_PROXY=teleport.example.com:443",
+		"AWS_PROFILE=dev",
+		"https_proxy=http://proxy:3128",
+	}
+	policy := ChildEnvPolicy{
+		Allow: []string{"PATH", "TELEPORT_*", "https_proxy", "kubectl:AWS_*"},
+		Set:   []string{"NO_PROXY=localhost:8080", "tsh:TELEPORT_LOGIN=ci"},
+	}
+
+	got := filterEnv(environ, "kubectl", policy)
+	want := []string{"PATH=/usr/bin", "TELEPORT_PROXY=teleport.example.com:443", "AWS_PROFILE=dev", "https_proxy=http://proxy:3128", "NO_PROXY=localhost:8080"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("kubectl env = %v, want %v", got, want)
+	}
+
+	got = filterEnv(environ, "tsh", policy)
+	want = []string{"PATH=/usr/bin", "TELEPORT_PROXY=teleport.example.com:443", "https_proxy=http://proxy:3128", "NO_PROXY=localhost:8080", "TELEPORT_LOGIN=ci"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("tsh env = %v, want %v", got, want)
+	}
+
+	// The migration switch keeps the complete environment.
+	got = filterEnv(environ, "tsh", ChildEnvPolicy{InheritAll: true})
+	if !reflect.DeepEqual(got, environ) {
+		t.Errorf("inherited env = %v, want %v", got, environ)
+	}
+}
+
+func TestSetChildEnvPolicyValidatesAssignments(t *testing.T) {
+	defer func(p ChildEnvPolicy) { childEnvPolicy = p }(childEnvPolicy)
+
+	for _, entry := range []string{"NOVALUE", "kubectl:=x"} {
+		if err := SetChildEnvPolicy(ChildEnvPolicy{Set: []string{entry}}); err == nil {
+			t.Errorf("expected %q to be rejected", entry)
+		}
+	}
+	if err := SetChildEnvPolicy(ChildEnvPolicy{Set: []string{"kubectl:KUBECACHEDIR=/tmp/cache"}}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestDefaultChildEnvAllowlistKeepsLoginAndExecPluginVariables(t *testing.T) {
+	environ := []string{
+		"DISPLAY=:0",
+		"WAYLAND_DISPLAY=wayland-0",
+		"BROWSER=firefox",
+		"DBUS_SESSION_BUS_ADDRESS=unix:path=/run/user/1000/bus",
+		"AWS_PROFILE=dev",
+		"AWS_SECRET_ACCESS_KEY=secret",
+		"AWS_SESSION_TOKEN=secret",
+		"GOOGLE_APPLICATION_CREDENTIALS=/home/jdoe/sa.json",
+		"CLOUDSDK_CORE_PROJECT=demo",
+		"GITHUB_TOKEN=secret",
+	}
+	policy := ChildEnvPolicy{Allow: DefaultChildEnvAllowlist}
+
+	// kubectl runs the cloud exec plugins and needs their credentials.
+	got := filterEnv(environ, "kubectl", policy)
+	if want := environ[:len(environ)-1]; !reflect.DeepEqual(got, want) {
+		t.Errorf("kubectl env = %v, want %v", got, want)
+	}
+
+	// tsh only needs the desktop session; cloud credentials stay away from it.
+	got = filterEnv(environ, "tsh", policy)
+	if want := environ[:4]; !reflect.DeepEqual(got, want) {
+		t.Errorf("tsh env = %v, want %v", got, want)
+	}
+}
@@ -2,20 +2,24 @@ package utils
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
 	"strings"
+	"time"
 )
 
-// LoginToKubeCluster executes `tsh kube login <clusterName>` to authenticate with a Teleport Kubernetes cluster.
+// runTshKubeLogin executes `tsh kube login <clusterName>` to authenticate with a Teleport Kubernetes cluster.
 // It captures and returns the standard output and standard error from the command.
+// Callers should use LoginToKubeCluster, which serializes and deduplicates concurrent logins.
 // Note: This function currently passes os.Stdin to the command, which might cause issues
 // if `tsh` prompts for interactive input (e.g., 2FA) in a non-interactive environment like the TUI.
 // - clusterName: The name of the Teleport Kubernetes cluster to log into.
 // Returns the stdout string, stderr string, and an error if the command execution fails.
-func LoginToKubeCluster(clusterName string) (stdout string, stderr string, err error) {
+func runTshKubeLogin(clusterName string) (stdout string, stderr string, err error) {
 	cmd := exec.Command("tsh", "kube", "login", clusterName)
+	cmd.Env = ChildEnv("tsh")
 
 	var stdoutBuf, stderrBuf bytes.Buffer
 	cmd.Stdout = &stdoutBuf
@@ -39,6 +43,19 @@ func LoginToKubeCluster(clusterName string) (stdout string, stderr string, err e
 	return stdoutStr, stderrStr, nil
 }
 
+// teleportContextPrefix is the prefix of the kube contexts created by `tsh kube login`.
+const teleportContextPrefix = "teleport.giantswarm.io-"
+
+// TeleportContextName returns the kube context `tsh kube login` creates for a cluster,
+// e.g. "teleport.giantswarm.io-myinstallation" for "myinstallation". Names that already carry the prefix are returned unchanged.
+// - cluster: The cluster identifier, e.g. "myinstallation" or "myinstallation-myworkloadcluster".
+func TeleportContextName(cluster string) string {
+	if strings.HasPrefix(cluster, teleportContextPrefix) {
+		return cluster
+	}
+	return teleportContextPrefix + cluster
+}
+
 // DetermineClusterProvider attempts to identify the cloud provider (e.g., AWS, Azure, GCP)
 // of a Kubernetes cluster by inspecting the `providerID` of the first node.
 // It uses `kubectl get nodes -o jsonpath={.items[0].spec.providerID}`.
@@ -50,8 +67,8 @@ func DetermineClusterProvider(contextName string) (string, error) {
 
 	// Apply Teleport prefix to context name if it doesn't already have it
 	kubectlContextName := contextName
-	if contextName != "" && !strings.HasPrefix(contextName, "teleport.giantswarm.io-") {
-		kubectlContextName = "teleport.giantswarm.io-" + contextName
+	if contextName != "" {
+		kubectlContextName = TeleportContextName(contextName)
 	}
 
 	// Command to get node information in JSON format
@@ -61,6 +78,7 @@ func DetermineClusterProvider(contextName string) (string, error) {
 	}
 
 	cmd := exec.Command("kubectl", args...)
+	cmd.Env = ChildEnv("kubectl")
 	output, err := cmd.Output()
 	if err != nil {
 		return "", fmt.Errorf("failed to get node provider information: %w", err)
@@ -101,6 +119,7 @@ func determineProviderFromLabels(contextName string) (string, error) {
 	}
 
 	cmd := exec.Command("kubectl", args...)
+	cmd.Env = ChildEnv("kubectl")
 	output, err := cmd.Output()
 	if err != nil {
 		return "", fmt.Errorf("failed to get node labels: %w", err)
@@ -126,6 +145,7 @@ func determineProviderFromLabels(contextName string) (string, error) {
 // Returns the context name (trimmed of whitespace) and an error if the command fails.
 func GetCurrentKubeContext() (string, error) {
 	cmd := exec.Command("kubectl", "config", "current-context")
+	cmd.Env = ChildEnv("kubectl")
 	output, err := cmd.Output()
 	if err != nil {
 		// If there's an error (e.g., kubectl not configured, no current context), return it.
@@ -142,6 +162,7 @@ func GetCurrentKubeContext() (string, error) {
 // Returns an error if the command fails, including the command's output in the error message.
 func SwitchKubeContext(contextName string) error {
 	cmd := exec.Command("kubectl", "config", "use-context", contextName)
+	cmd.Env = ChildEnv("kubectl")
 	// We don't want to inherit os.Stdout/Stderr directly for this one,
 	// as successful output is minimal and errors will be captured.
 	output, err := cmd.CombinedOutput()
@@ -150,3 +171,28 @@ func SwitchKubeContext(contextName string) error {
 	}
 	return nil
 }
+
+// kubectlCommandTimeout bounds how long an ad-hoc kubectl command may run.
+const kubectlCommandTimeout = 30 * time.Second
+
+// RunKubectl runs `kubectl --context <contextName> <args...>` without a shell and returns its combined output.
+// Arguments are passed as-is, so shell features like pipes or globbing are not available.
+// The command is killed after kubectlCommandTimeout so that watches or hanging requests cannot block forever.
+// - contextName: The Kubernetes context the command is scoped to.
+// - args: The kubectl arguments, e.g. ["get", "pods", "-A"].
+// Returns the combined stdout/stderr and an error if kubectl fails or times out.
+func RunKubectl(contextName string, args []string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), kubectlCommandTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "kubectl", append([]string{"--context", contextName}, args...)...)
+	cmd.Env = ChildEnv("kubectl")
+	output, err := cmd.CombinedOutput()
+	if ctx.Err() == context.DeadlineExceeded {
+		return string(output), fmt.Errorf("kubectl timed out after %s", kubectlCommandTimeout)
+	}
+	if err != nil {
+		return string(output), fmt.Errorf("kubectl failed: %w", err)
+	}
+	return string(output), nil
+}
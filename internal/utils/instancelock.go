@@ -0,0 +1,258 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"runtime"
+	"syscall"
+	"time"
+)
+
+// Timing of AcquireInstanceLock.
+const (
+	// lockRetryInterval is the pause before retrying while another instance is writing or taking over the lock.
+	lockRetryInterval = 50 * time.Millisecond
+	// lockAttempts bounds the retries of AcquireInstanceLock.
+	lockAttempts = 40
+	// lockWriteGrace is how old an unreadable lock file must be before it is considered stale;
+	// younger files may still be written by an older envctl that did not create them atomically.
+	lockWriteGrace = 5 * time.Second
+	// takeoverGuardTimeout is how old a takeover guard must be before it is considered abandoned.
+	takeoverGuardTimeout = 10 * time.Second
+)
+
+// Permissions of the lock directory and lock files. The directory is group-writable with the setgid bit, so that
+// users sharing a host through a common group can create, take over and remove each other's locks. Lock files
+// are readable by everyone, so that any instance can show the holder.
+const (
+	lockDirMode  = 0o775 | os.ModeSetgid
+	lockFileMode = 0o664
+)
+
+// DefaultInstanceLockPath returns the lock file used for leader election between envctl instances.
+// It lives in a directory shared by all users of the host (/tmp/envctl, or %ProgramData%\envctl on Windows),
+// so that the instances of several people sharing a VM elect one leader without further configuration.
+func DefaultInstanceLockPath() string {
+	if runtime.GOOS == "windows" {
+		if dir := os.Getenv("ProgramData"); dir != "" {
+			return filepath.Join(dir, "envctl", "instance.lock")
+		}
+		return filepath.Join(os.TempDir(), "envctl", "instance.lock")
+	}
+	// Not os.TempDir(): $TMPDIR is per user on macOS and in some login setups.
+	return filepath.Join("/tmp", "envctl", "instance.lock")
+}
+
+// LockHolder describes the envctl instance currently holding the instance lock.
+type LockHolder struct {
+	PID   int       `json:"pid"`   // Process ID of the holder.
+	User  string    `json:"user"`  // OS user running the holder.
+	Host  string    `json:"host"`  // Hostname of the machine running the holder.
+	Since time.Time `json:"since"` // When the lock was acquired.
+}
+
+// String formats the holder for display, e.g. "alice@vm-1 (pid 1234, since 10:32:01)".
+func (h LockHolder) String() string {
+	return fmt.Sprintf("%s@%s (pid %d, since %s)", h.User, h.Host, h.PID, h.Since.Format("15:04:05"))
+}
+
+// InstanceLock is a held instance lock. Release it when the instance shuts down.
+type InstanceLock struct {
+	path   string
+	holder LockHolder // What was written to the lock file, to recognise it on release.
+}
+
+// AcquireInstanceLock attempts to become the leader envctl instance. The holder information is written to a
+// temporary file first and then hard-linked to path, so the lock file never exists without its complete content.
+// If another live instance holds the lock, it returns a nil lock together with the current holder.
+// A lock left behind by a process that no longer exists is considered stale and is taken over; only one
+// instance at a time may do so (see removeStaleLock), so concurrent takeovers cannot remove each other's locks.
+// - path: The lock file path, typically DefaultInstanceLockPath().
+// Returns the acquired lock (or nil), the current holder if the lock is held elsewhere, and any unexpected error.
+func AcquireInstanceLock(path string) (*InstanceLock, *LockHolder, error) {
+	if err := ensureLockDir(filepath.Dir(path)); err != nil {
+		return nil, nil, fmt.Errorf("failed to create directory of instance lock %s: %w", path, err)
+	}
+	holder := currentLockHolder()
+	for attempt := 0; attempt < lockAttempts; attempt++ {
+		err := linkLockFile(path, holder)
+		if err == nil {
+			return &InstanceLock{path: path, holder: holder}, nil, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, nil, fmt.Errorf("failed to create instance lock %s: %w", path, permissionHint(err, path))
+		}
+
+		info, statErr := os.Stat(path)
+		if errors.Is(statErr, os.ErrNotExist) {
+			continue // Released in the meantime.
+		}
+		if statErr != nil {
+			return nil, nil, fmt.Errorf("failed to inspect instance lock %s: %w", path, statErr)
+		}
+		current, readErr := readLockHolder(path)
+		switch {
+		case readErr == nil && processAlive(current.PID):
+			return nil, current, nil
+		case readErr != nil && time.Since(info.ModTime()) < lockWriteGrace:
+			time.Sleep(lockRetryInterval) // Possibly still being written.
+			continue
+		}
+
+		taken, err := removeStaleLock(path, info)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !taken {
+			time.Sleep(lockRetryInterval) // Another instance is taking over the lock.
+		}
+	}
+	return nil, nil, fmt.Errorf("failed to acquire instance lock %s: gave up after %d attempts", path, lockAttempts)
+}
+
+// Release removes the lock file, allowing another instance to become the leader. The file is left in place
+// if it no longer belongs to this instance, e.g. because it was taken over after being considered stale.
+// It is safe to call on a nil lock.
+func (l *InstanceLock) Release() error {
+	if l == nil {
+		return nil
+	}
+	current, err := readLockHolder(l.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to release instance lock %s: %w", l.path, err)
+	}
+	if current.PID != l.holder.PID || !current.Since.Equal(l.holder.Since) {
+		return fmt.Errorf("not releasing instance lock %s: it is now held by %s", l.path, current)
+	}
+	if err := os.Remove(l.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to release instance lock %s: %w", l.path, err)
+	}
+	return nil
+}
+
+// ensureLockDir creates the lock directory with lockDirMode if it does not exist yet.
+// An existing directory is left as it is; it may belong to another user.
+func ensureLockDir(dir string) error {
+	if _, err := os.Stat(dir); !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if err := os.MkdirAll(dir, lockDirMode); err != nil {
+		return err
+	}
+	// MkdirAll applies the umask, which usually removes the group write permission. If another instance
+	// created the directory at the same time, it is not ours to change.
+	if err := os.Chmod(dir, lockDirMode); err != nil && !errors.Is(err, os.ErrPermission) {
+		return err
+	}
+	return nil
+}
+
+// linkLockFile atomically creates the lock file at path with the holder information.
+// Returns an error wrapping os.ErrExist if the lock file already exists.
+func linkLockFile(path string, holder LockHolder) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".envctl-instance-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	encodeErr := json.NewEncoder(tmp).Encode(holder)
+	closeErr := tmp.Close()
+	if err := errors.Join(encodeErr, closeErr); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), lockFileMode); err != nil {
+		return err
+	}
+	return os.Link(tmp.Name(), path)
+}
+
+// removeStaleLock removes the stale lock file at path. The takeover is guarded by a second lock file,
+// so that only one instance removes the stale lock at a time, and it only removes the very file that was
+// found to be stale: a fresh lock created by another instance in the meantime is left alone.
+// - stale: The file info of the lock file that was found to be stale.
+// Returns false if another instance is taking over the lock right now, and an error if the lock cannot be removed.
+func removeStaleLock(path string, stale os.FileInfo) (bool, error) {
+	guard := path + ".takeover"
+	g, err := os.OpenFile(guard, os.O_WRONLY|os.O_CREATE|os.O_EXCL, lockFileMode)
+	if errors.Is(err, os.ErrExist) {
+		if info, statErr := os.Stat(guard); statErr == nil && time.Since(info.ModTime()) > takeoverGuardTimeout {
+			_ = os.Remove(guard) // Abandoned by an instance that died during its takeover.
+		}
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to take over stale instance lock %s: %w", path, permissionHint(err, path))
+	}
+	_ = g.Close()
+	defer os.Remove(guard)
+
+	info, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return true, nil
+	}
+	if err != nil || !os.SameFile(info, stale) {
+		return true, nil // Replaced in the meantime; the caller inspects the new lock.
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return false, fmt.Errorf("failed to remove stale instance lock %s: %w", path, permissionHint(err, path))
+	}
+	return true, nil
+}
+
+// permissionHint explains a permission error on the lock file, which usually means it belongs to another user.
+func permissionHint(err error, path string) error {
+	if !errors.Is(err, os.ErrPermission) {
+		return err
+	}
+	return fmt.Errorf("%w (the lock file or its directory belongs to another user; users sharing %s need a common group owning its directory, or use another --lock-file)", err, path)
+}
+
+// currentLockHolder describes the running process as a lock holder.
+func currentLockHolder() LockHolder {
+	holder := LockHolder{PID: os.Getpid(), User: "unknown", Host: "unknown", Since: time.Now()}
+	if u, err := user.Current(); err == nil {
+		holder.User = u.Username
+	}
+	if h, err := os.Hostname(); err == nil {
+		holder.Host = h
+	}
+	return holder
+}
+
+// readLockHolder parses the holder information stored in the lock file.
+func readLockHolder(path string) (*LockHolder, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var holder LockHolder
+	if err := json.Unmarshal(data, &holder); err != nil {
+		return nil, fmt.Errorf("malformed instance lock %s: %w", path, err)
+	}
+	return &holder, nil
+}
+
+// processAlive reports whether a process with the given PID exists on this host.
+func processAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	// On Windows FindProcess already fails for non-existent processes; signals are not supported there.
+	if runtime.GOOS == "windows" {
+		return true
+	}
+	err = p.Signal(syscall.Signal(0))
+	// EPERM means the process exists but belongs to another user.
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
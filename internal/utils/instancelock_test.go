@@ -0,0 +1,171 @@
+package utils
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// writeLockFile writes a lock file as a holder with the given PID would have.
+func writeLockFile(t *testing.T, path string, pid int) {
+	t.Helper()
+	data, err := json.Marshal(LockHolder{PID: pid, User: "bob", Host: "vm-1", Since: time.Now().Add(-time.Hour)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// acquireConcurrently lets n goroutines acquire the lock at the same time and returns the acquired locks.
+func acquireConcurrently(t *testing.T, path string, n int) []*InstanceLock {
+	t.Helper()
+	var (
+		mu    sync.Mutex
+		locks []*InstanceLock
+		wg    sync.WaitGroup
+	)
+	start := make(chan struct{})
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			lock, holder, err := AcquireInstanceLock(path)
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+			if lock == nil && holder == nil {
+				t.Errorf("expected either the lock or its holder")
+				return
+			}
+			if lock != nil {
+				mu.Lock()
+				locks = append(locks, lock)
+				mu.Unlock()
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+	return locks
+}
+
+func TestAcquireInstanceLockConcurrently(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "instance.lock")
+	locks := acquireConcurrently(t, path, 20)
+	if len(locks) != 1 {
+		t.Fatalf("expected exactly one leader, got %d", len(locks))
+	}
+	holder, err := readLockHolder(path)
+	if err != nil {
+		t.Fatalf("expected a complete lock file: %v", err)
+	}
+	if holder.PID != os.Getpid() {
+		t.Errorf("expected this process as holder, got %s", holder)
+	}
+	if err := locks[0].Release(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected the lock file to be removed on release")
+	}
+}
+
+func TestAcquireInstanceLockTakesOverStaleLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "instance.lock")
+	writeLockFile(t, path, 0) // No process has PID 0.
+
+	locks := acquireConcurrently(t, path, 10)
+	if len(locks) != 1 {
+		t.Fatalf("expected exactly one instance to take over the stale lock, got %d", len(locks))
+	}
+	if _, err := os.Stat(path + ".takeover"); !os.IsNotExist(err) {
+		t.Errorf("expected the takeover guard to be removed")
+	}
+}
+
+func TestAcquireInstanceLockUnreadableLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "instance.lock")
+	if err := os.WriteFile(path, []byte(`{"pid":`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// A fresh unreadable lock may still be written and must not be taken over.
+	if lock, _, err := AcquireInstanceLock(path); lock != nil || err == nil {
+		t.Fatalf("expected a fresh unreadable lock to be left alone, got lock %v, error %v", lock, err)
+	}
+
+	old := time.Now().Add(-time.Minute)
+	if err := os.Chtimes(path, old, old); err != nil {
+		t.Fatal(err)
+	}
+	lock, _, err := AcquireInstanceLock(path)
+	if err != nil || lock == nil {
+		t.Fatalf("expected an old unreadable lock to be taken over, got error %v", err)
+	}
+	_ = lock.Release()
+}
+
+func TestReleaseKeepsLockOfAnotherHolder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "instance.lock")
+	lock, _, err := AcquireInstanceLock(path)
+	if err != nil || lock == nil {
+		t.Fatalf("expected to acquire the lock, got error %v", err)
+	}
+	// Another instance took the lock over in the meantime.
+	writeLockFile(t, path, os.Getpid()+1)
+
+	if err := lock.Release(); err == nil || !strings.Contains(err.Error(), "now held by") {
+		t.Errorf("expected release to refuse, got %v", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("expected the other holder's lock file to be kept: %v", err)
+	}
+}
+
+func TestDefaultInstanceLockPathIsShared(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the default lock directory is in %ProgramData% on Windows")
+	}
+	// The path must not depend on per-user settings, or the instances of different users would not see each other.
+	t.Setenv("XDG_RUNTIME_DIR", "/run/user/1000")
+	t.Setenv("TMPDIR", "/tmp/alice")
+	if got := DefaultInstanceLockPath(); got != filepath.Join("/tmp", "envctl", "instance.lock") {
+		t.Errorf("got %s", got)
+	}
+}
+
+func TestAcquireInstanceLockCreatesSharedDirectory(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Unix permissions")
+	}
+	path := filepath.Join(t.TempDir(), "envctl", "instance.lock")
+	lock, _, err := AcquireInstanceLock(path)
+	if err != nil || lock == nil {
+		t.Fatalf("expected to acquire the lock, got %v", err)
+	}
+	defer lock.Release()
+
+	dir, err := os.Stat(filepath.Dir(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dir.Mode().Perm() != 0o775 || dir.Mode()&os.ModeSetgid == 0 {
+		t.Errorf("lock directory mode = %s, want group-writable with setgid", dir.Mode())
+	}
+	file, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if file.Mode().Perm() != lockFileMode {
+		t.Errorf("lock file mode = %s, want %s", file.Mode().Perm(), os.FileMode(lockFileMode))
+	}
+}
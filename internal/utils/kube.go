@@ -3,6 +3,7 @@ package utils
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/http"
 	"strconv"
 	"strings"
@@ -127,7 +128,7 @@ func StartPortForwardClientGo(
 	// 4. Determine Target Pod
 	podName, err := getPodNameForPortForward(clientset, namespace, serviceArg, uint16(remotePort))
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to determine target pod for %q in %q: %w", serviceArg, namespace, err)
+		return nil, "", fmt.Errorf("failed to determine target pod for %q in %q: %w", serviceArg, namespace, AnnotateKubeProxyError(err, restConfig))
 	}
 
 	// 5. Create PortForwarder URL
@@ -152,10 +153,11 @@ func StartPortForwardClientGo(
 	stdOutWriter := &tuiLogWriter{label: pfLabel, sendUpdate: sendUpdate, asError: false}
 	stdErrWriter := &tuiLogWriter{label: pfLabel, sendUpdate: sendUpdate, asError: true}
 
-	// Using NewOnAddresses to specify listen on 127.0.0.1
+	// Using NewOnAddresses to listen on the configured addresses (127.0.0.1 by default, see SetBindAddresses).
 	// localPort can be 0 to pick a random available port.
 	// If localPort is 0, GetPorts() must be used after ready.
-	addresses := []string{"127.0.0.1"} // Listen on localhost
+	addresses := BindAddressesFor(pfLabel)
+	listenHost := addresses[0]
 
 	forwarder, err := portforward.NewOnAddresses(dialer, addresses, ports, stopChan, readyChan, stdOutWriter, stdErrWriter)
 	if err != nil {
@@ -167,6 +169,11 @@ func StartPortForwardClientGo(
 
 	// Send useful debug messages without overwhelming the log
 	sendUpdate("", fmt.Sprintf("Starting port forward to pod %s", podName), false, false)
+	for _, address := range addresses {
+		if !IsLoopbackAddress(address) {
+			sendUpdate("", fmt.Sprintf("Warning: listening on %s, reachable from other hosts without authentication", address), false, false)
+		}
+	}
 
 	// 7. Run Asynchronously
 	go func() {
@@ -196,9 +203,9 @@ func StartPortForwardClientGo(
 			actualPorts, portErr := forwarder.GetPorts()
 			var fwdDetail string
 			if portErr == nil && len(actualPorts) > 0 {
-				fwdDetail = fmt.Sprintf("Forwarding from 127.0.0.1:%d to pod port %d", actualPorts[0].Local, actualPorts[0].Remote)
+				fwdDetail = fmt.Sprintf("Forwarding from %s to pod port %d", net.JoinHostPort(listenHost, strconv.Itoa(int(actualPorts[0].Local))), actualPorts[0].Remote)
 			} else {
-				fwdDetail = fmt.Sprintf("Forwarding from 127.0.0.1:%s to pod port %s", localPortStr, remotePortStr)
+				fwdDetail = fmt.Sprintf("Forwarding from %s to pod port %s", net.JoinHostPort(listenHost, localPortStr), remotePortStr)
 				if portErr != nil {
 					sendUpdate("", fmt.Sprintf("Warning: could not get bound local port: %v", portErr), true, false)
 				}
@@ -223,26 +230,53 @@ func StartPortForwardClientGo(
 	return stopChan, initialStatusLog, nil
 }
 
-// getPodNameForPortForward resolves a service argument (like "service/my-svc" or "pod/my-pod")
-// to a specific, preferably ready, pod name that can be used as a target for port forwarding.
-// If the argument is a service, it lists pods matching the service's selector and picks a running/ready one.
+// getPodNameForPortForward resolves a target argument to a specific, preferably ready, pod name that can be
+// used as a target for port forwarding. Supported targets:
+//   - "pod/<name>": the pod itself.
+//   - "pod/<label selector>", e.g. "pod/app=debug,tier=sidecar": a ready pod matching the selector.
+//   - "deployment/<name>": a ready pod matching the deployment's selector.
+//   - "service/<name>": a ready pod matching the service's selector.
+//
+// The target is resolved on every (re)start of the port-forward, so a forward to a deployment, a service or a
+// selector follows the pod when it is replaced.
 // - clientset: An initialized Kubernetes clientset.
-// - namespace: The namespace to look for the service/pod in.
+// - namespace: The namespace to look for the target in.
 // - serviceArg: The string identifying the target (e.g., "service/my-service", "pod/my-pod").
 // - remotePodTargetPort: The port on the pod that the port-forward aims to connect to. Used to (softly) check service port exposure.
 // Returns the name of a suitable pod or an error if one cannot be found.
 func getPodNameForPortForward(clientset kubernetes.Interface, namespace, serviceArg string, remotePodTargetPort uint16) (string, error) {
 	parts := strings.SplitN(serviceArg, "/", 2)
-	if len(parts) != 2 {
-		return "", fmt.Errorf("invalid service/pod string %q, expected type/name (e.g., pod/my-pod or service/my-service)", serviceArg)
+	if len(parts) != 2 || parts[1] == "" {
+		return "", fmt.Errorf("invalid target %q, expected type/name (e.g., pod/my-pod, pod/app=debug, deployment/my-deployment or service/my-service)", serviceArg)
 	}
-	resourceType, resourceName := parts[0], parts[1]
-
-	if strings.ToLower(resourceType) == "pod" {
-		// For a pod, just return its name. The port is already known.
-		// We could verify the pod exists, but port-forward will fail if not.
-		return resourceName, nil
-	} else if strings.ToLower(resourceType) == "service" {
+	resourceType, resourceName := strings.ToLower(parts[0]), parts[1]
+
+	switch resourceType {
+	case "pod":
+		if !strings.ContainsAny(resourceName, "=!") && !strings.Contains(resourceName, " in ") {
+			// For a pod, just return its name. The port is already known.
+			// We could verify the pod exists, but port-forward will fail if not.
+			return resourceName, nil
+		}
+		selector, err := labels.Parse(resourceName)
+		if err != nil {
+			return "", fmt.Errorf("invalid label selector %q: %w", resourceName, err)
+		}
+		return readyPodForSelector(clientset, namespace, selector, fmt.Sprintf("selector %s in %s", selector.String(), namespace))
+	case "deployment":
+		dep, err := clientset.AppsV1().Deployments(namespace).Get(context.TODO(), resourceName, metav1.GetOptions{})
+		if err != nil {
+			return "", fmt.Errorf("failed to get deployment %s/%s: %w", namespace, resourceName, err)
+		}
+		selector, err := metav1.LabelSelectorAsSelector(dep.Spec.Selector)
+		if err != nil {
+			return "", fmt.Errorf("invalid selector of deployment %s/%s: %w", namespace, resourceName, err)
+		}
+		if selector.Empty() {
+			return "", fmt.Errorf("deployment %s/%s has no selector, cannot find its pods", namespace, resourceName)
+		}
+		return readyPodForSelector(clientset, namespace, selector, fmt.Sprintf("deployment %s/%s", namespace, resourceName))
+	case "service":
 		svc, err := clientset.CoreV1().Services(namespace).Get(context.TODO(), resourceName, metav1.GetOptions{})
 		if err != nil {
 			return "", fmt.Errorf("failed to get service %s/%s: %w", namespace, resourceName, err)
@@ -269,48 +303,55 @@ func getPodNameForPortForward(clientset kubernetes.Interface, namespace, service
 		if len(svc.Spec.Selector) == 0 {
 			return "", fmt.Errorf("service %s/%s has no selector, cannot find backing pods", namespace, resourceName)
 		}
+		return readyPodForSelector(clientset, namespace, labels.SelectorFromSet(svc.Spec.Selector), fmt.Sprintf("service %s/%s", namespace, resourceName))
+	}
+	return "", fmt.Errorf("unsupported resource type %q in %q", parts[0], serviceArg)
+}
 
-		selector := labels.SelectorFromSet(svc.Spec.Selector)
-		podList, err := clientset.CoreV1().Pods(namespace).List(context.TODO(), metav1.ListOptions{LabelSelector: selector.String()})
-		if err != nil {
-			return "", fmt.Errorf("failed to list pods for service %s/%s: %w", namespace, resourceName, err)
+// readyPodForSelector lists the pods matching a label selector and picks one that is running and ready.
+// - what: Describes the target in errors, e.g. "service monitoring/grafana".
+// Returns the name of the pod or an error if no ready pod matches.
+func readyPodForSelector(clientset kubernetes.Interface, namespace string, selector labels.Selector, what string) (string, error) {
+	podList, err := clientset.CoreV1().Pods(namespace).List(context.TODO(), metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return "", fmt.Errorf("failed to list pods for %s: %w", what, err)
+	}
+	if len(podList.Items) == 0 {
+		return "", fmt.Errorf("no pods found for %s with selector %s", what, selector.String())
+	}
+
+	// Pick a ready pod
+	for _, pod := range podList.Items {
+		if pod.Status.Phase != corev1.PodRunning || pod.DeletionTimestamp != nil {
+			continue
+		}
+		isReady := false
+		for _, cond := range pod.Status.Conditions {
+			if cond.Type == corev1.PodReady && cond.Status == corev1.ConditionTrue {
+				isReady = true
+				break
+			}
 		}
-		if len(podList.Items) == 0 {
-			return "", fmt.Errorf("no pods found for service %s/%s with selector %s", namespace, resourceName, selector.String())
+		if !isReady {
+			continue
 		}
-
-		// Pick a ready pod
-		for _, pod := range podList.Items {
-			if pod.Status.Phase == corev1.PodRunning {
-				isReady := false
-				for _, cond := range pod.Status.Conditions {
-					if cond.Type == corev1.PodReady && cond.Status == corev1.ConditionTrue {
-						isReady = true
-						break
-					}
-				}
-				if isReady {
-					// Also check if containers are ready (optional, but good)
-					allContainersReady := true
-					if len(pod.Status.ContainerStatuses) == 0 && len(pod.Spec.Containers) > 0 {
-						// Pod is running but container statuses not yet reported, might be initializing
-						allContainersReady = false
-					}
-					for _, cs := range pod.Status.ContainerStatuses {
-						if !cs.Ready {
-							allContainersReady = false
-							break
-						}
-					}
-					if allContainersReady {
-						return pod.Name, nil
-					}
-				}
+		// Also check if containers are ready (optional, but good)
+		allContainersReady := true
+		if len(pod.Status.ContainerStatuses) == 0 && len(pod.Spec.Containers) > 0 {
+			// Pod is running but container statuses not yet reported, might be initializing
+			allContainersReady = false
+		}
+		for _, cs := range pod.Status.ContainerStatuses {
+			if !cs.Ready {
+				allContainersReady = false
+				break
 			}
 		}
-		return "", fmt.Errorf("no ready pods found for service %s/%s (selector: %s)", namespace, resourceName, selector.String())
+		if allContainersReady {
+			return pod.Name, nil
+		}
 	}
-	return "", fmt.Errorf("unsupported resource type %q in %q", resourceType, serviceArg)
+	return "", fmt.Errorf("no ready pods found for %s (selector: %s)", what, selector.String())
 }
 
 // GetNodeStatusClientGo retrieves the number of ready and total nodes in a cluster using client-go.
@@ -337,7 +378,7 @@ func GetNodeStatusClientGo(kubeContext string) (readyNodes int, totalNodes int,
 	// 3. List Nodes
 	nodeList, err := clientset.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{})
 	if err != nil {
-		return 0, 0, fmt.Errorf("failed to list nodes in context %q: %w", kubeContext, err)
+		return 0, 0, fmt.Errorf("failed to list nodes in context %q: %w", kubeContext, AnnotateKubeProxyError(err, restConfig))
 	}
 
 	totalNodes = len(nodeList.Items)
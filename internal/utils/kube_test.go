@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func testPod(name string, podLabels map[string]string, ready bool) *corev1.Pod {
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "debug", Labels: podLabels},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "main"}}},
+		Status: corev1.PodStatus{
+			Phase:             corev1.PodRunning,
+			Conditions:        []corev1.PodCondition{{Type: corev1.PodReady, Status: status}},
+			ContainerStatuses: []corev1.ContainerStatus{{Name: "main", Ready: ready}},
+		},
+	}
+}
+
+func TestGetPodNameForPortForward(t *testing.T) {
+	clientset := fake.NewSimpleClientset(
+		testPod("toolbox-old", map[string]string{"app": "toolbox"}, false),
+		testPod("toolbox-new", map[string]string{"app": "toolbox"}, true),
+		testPod("sidecar-0", map[string]string{"app.kubernetes.io/name": "sidecar"}, true),
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "toolbox", Namespace: "debug"},
+			Spec:       appsv1.DeploymentSpec{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "toolbox"}}},
+		},
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "sidecar", Namespace: "debug"},
+			Spec:       corev1.ServiceSpec{Selector: map[string]string{"app.kubernetes.io/name": "sidecar"}},
+		},
+	)
+
+	tests := []struct {
+		target  string
+		want    string
+		wantErr bool
+	}{
+		{target: "pod/anything-0", want: "anything-0"},
+		{target: "pod/app=toolbox", want: "toolbox-new"},
+		{target: "pod/app.kubernetes.io/name=sidecar", want: "sidecar-0"},
+		{target: "deployment/toolbox", want: "toolbox-new"},
+		{target: "service/sidecar", want: "sidecar-0"},
+		{target: "pod/app=missing", wantErr: true},
+		{target: "deployment/missing", wantErr: true},
+		{target: "statefulset/toolbox", wantErr: true},
+		{target: "toolbox", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			got, err := getPodNameForPortForward(clientset, "debug", tt.target, 8080)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
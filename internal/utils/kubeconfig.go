@@ -0,0 +1,106 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// DefaultIsolatedKubeconfigPath returns the kubeconfig file envctl uses in isolated mode,
+// located in the user's config directory (e.g. ~/.config/envctl/kubeconfig on Linux).
+func DefaultIsolatedKubeconfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "envctl", "kubeconfig")
+}
+
+// IsolatedKubeconfig is the kubeconfig envctl uses instead of the user's global one, see UseIsolatedKubeconfig.
+type IsolatedKubeconfig struct {
+	Path      string // Absolute path of the kubeconfig file.
+	temporary bool   // True if the file belongs to this instance and is removed by Close.
+	previous  string // KUBECONFIG before it was overridden.
+	hadEnv    bool   // Whether KUBECONFIG was set before.
+}
+
+// UseIsolatedKubeconfig makes envctl read and write Kubernetes contexts in its own kubeconfig file
+// instead of the user's global one. It sets KUBECONFIG for the envctl process, which is honoured by
+// `tsh kube login`, `kubectl` and the client-go port-forwards alike, so logins and context switches
+// performed by envctl leave the global current-context untouched. The file is kept on Close, so
+// logins survive restarts and other shells can use it.
+// - path: The kubeconfig file to use. Its directory is created if needed; tsh creates the file on first login.
+// Returns the isolated kubeconfig, or an error if the directory cannot be created or the environment cannot be updated.
+func UseIsolatedKubeconfig(path string) (*IsolatedKubeconfig, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve kubeconfig path %s: %w", path, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(absPath), 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create directory for kubeconfig %s: %w", absPath, err)
+	}
+	return overrideKubeconfig(absPath, false)
+}
+
+// UseTemporaryKubeconfig is UseIsolatedKubeconfig with a new, empty kubeconfig file for this envctl instance only.
+// The file is created in the system temp directory, readable only by the user, and removed by Close.
+// Returns the isolated kubeconfig, or an error if the file cannot be created or the environment cannot be updated.
+func UseTemporaryKubeconfig() (*IsolatedKubeconfig, error) {
+	f, err := os.CreateTemp("", "envctl-kubeconfig-*.yaml")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary kubeconfig: %w", err)
+	}
+	_ = f.Close()
+	kubeconfig, err := overrideKubeconfig(f.Name(), true)
+	if err != nil {
+		_ = os.Remove(f.Name())
+		return nil, err
+	}
+	return kubeconfig, nil
+}
+
+// overrideKubeconfig points KUBECONFIG at path, remembering the previous value for Close.
+func overrideKubeconfig(path string, temporary bool) (*IsolatedKubeconfig, error) {
+	previous, hadEnv := os.LookupEnv("KUBECONFIG")
+	if err := os.Setenv("KUBECONFIG", path); err != nil {
+		return nil, fmt.Errorf("failed to set KUBECONFIG to %s: %w", path, err)
+	}
+	return &IsolatedKubeconfig{Path: path, temporary: temporary, previous: previous, hadEnv: hadEnv}, nil
+}
+
+// Close restores the previous KUBECONFIG and removes the kubeconfig file if it is temporary.
+// It is safe to call on a nil kubeconfig.
+func (k *IsolatedKubeconfig) Close() error {
+	if k == nil {
+		return nil
+	}
+	var err error
+	if k.hadEnv {
+		err = os.Setenv("KUBECONFIG", k.previous)
+	} else {
+		err = os.Unsetenv("KUBECONFIG")
+	}
+	if k.temporary {
+		if rmErr := os.Remove(k.Path); rmErr != nil && !errors.Is(rmErr, os.ErrNotExist) {
+			err = errors.Join(err, fmt.Errorf("failed to remove temporary kubeconfig %s: %w", k.Path, rmErr))
+		}
+	}
+	return err
+}
+
+// GetKubeContextNames returns the names of all contexts in the active kubeconfig (honouring KUBECONFIG).
+// It is used to tell which clusters envctl is already logged into.
+func GetKubeContextNames() ([]string, error) {
+	config, err := clientcmd.NewDefaultClientConfigLoadingRules().Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+	names := make([]string, 0, len(config.Contexts))
+	for name := range config.Contexts {
+		names = append(names, name)
+	}
+	return names, nil
+}
@@ -0,0 +1,91 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"testing"
+)
+
+func TestUseTemporaryKubeconfig(t *testing.T) {
+	t.Setenv("KUBECONFIG", "/home/jdoe/.kube/config")
+	t.Setenv("TMPDIR", t.TempDir())
+
+	kubeconfig, err := UseTemporaryKubeconfig()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	info, err := os.Stat(kubeconfig.Path)
+	if err != nil {
+		t.Fatalf("expected the temporary kubeconfig to exist: %v", err)
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm() != 0o600 {
+		t.Errorf("expected the temporary kubeconfig to be private, got %v", info.Mode().Perm())
+	}
+	if got := os.Getenv("KUBECONFIG"); got != kubeconfig.Path {
+		t.Errorf("expected KUBECONFIG to point at %s, got %s", kubeconfig.Path, got)
+	}
+
+	if err := kubeconfig.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := os.Stat(kubeconfig.Path); !os.IsNotExist(err) {
+		t.Errorf("expected the temporary kubeconfig to be removed on exit")
+	}
+	if got := os.Getenv("KUBECONFIG"); got != "/home/jdoe/.kube/config" {
+		t.Errorf("expected KUBECONFIG to be restored, got %s", got)
+	}
+}
+
+func TestUseIsolatedKubeconfig(t *testing.T) {
+	t.Setenv("KUBECONFIG", "")
+	os.Unsetenv("KUBECONFIG")
+	path := filepath.Join(t.TempDir(), "envctl", "kubeconfig")
+
+	kubeconfig, err := UseIsolatedKubeconfig(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := os.Getenv("KUBECONFIG"); got != path {
+		t.Fatalf("expected KUBECONFIG to point at %s, got %s", path, got)
+	}
+
+	// Contexts are read from the isolated kubeconfig.
+	config := `apiVersion: v1
+kind: Config
+clusters:
+- name: alpha
+  cluster: {server: "https://alpha.example.com"}
+users:
+- name: jdoe
+  user: {}
+contexts:
+- name: teleport.giantswarm.io-alpha
+  context: {cluster: alpha, user: jdoe}
+- name: teleport.giantswarm.io-alpha-beta
+  context: {cluster: alpha, user: jdoe}
+`
+	if err := os.WriteFile(path, []byte(config), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	names, err := GetKubeContextNames()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	sort.Strings(names)
+	if len(names) != 2 || names[0] != "teleport.giantswarm.io-alpha" {
+		t.Errorf("expected the contexts of the isolated kubeconfig, got %v", names)
+	}
+
+	// A persistent isolated kubeconfig is kept; KUBECONFIG is unset again as before.
+	if err := kubeconfig.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("expected the isolated kubeconfig to be kept: %v", err)
+	}
+	if _, ok := os.LookupEnv("KUBECONFIG"); ok {
+		t.Errorf("expected KUBECONFIG to be unset again")
+	}
+}
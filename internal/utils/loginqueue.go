@@ -0,0 +1,55 @@
+package utils
+
+import "sync"
+
+// loginCall is a single in-flight `tsh kube login` whose result is shared by all callers
+// that requested a login for the same cluster while it was running.
+type loginCall struct {
+	done    chan struct{} // Closed once the login has finished and the result fields are set.
+	waiters int           // Callers waiting for the result besides the one running the login; guarded by loginMu.
+	stdout  string
+	stderr  string
+	err     error
+}
+
+var (
+	// loginMu guards inFlightLogins.
+	loginMu sync.Mutex
+	// inFlightLogins maps a cluster name to its currently running login.
+	inFlightLogins = make(map[string]*loginCall)
+	// loginSerial ensures only one `tsh kube login` writes to the kubeconfig at a time.
+	loginSerial sync.Mutex
+	// tshKubeLoginFunc performs the actual login; replaced in tests.
+	tshKubeLoginFunc = runTshKubeLogin
+)
+
+// LoginToKubeCluster authenticates with a Teleport Kubernetes cluster via `tsh kube login <clusterName>`.
+// Concurrent requests for the same cluster are coalesced into a single login whose result is shared
+// with every caller, and logins for different clusters are serialized, because parallel `tsh` runs
+// can corrupt the kubeconfig they all write to.
+// - clusterName: The name of the Teleport Kubernetes cluster to log into.
+// Returns the stdout string, stderr string, and an error if the command execution fails.
+func LoginToKubeCluster(clusterName string) (stdout string, stderr string, err error) {
+	loginMu.Lock()
+	if call, ok := inFlightLogins[clusterName]; ok {
+		// Another caller is already logging into this cluster; wait for its result.
+		call.waiters++
+		loginMu.Unlock()
+		<-call.done
+		return call.stdout, call.stderr, call.err
+	}
+	call := &loginCall{done: make(chan struct{})}
+	inFlightLogins[clusterName] = call
+	loginMu.Unlock()
+
+	loginSerial.Lock()
+	call.stdout, call.stderr, call.err = tshKubeLoginFunc(clusterName)
+	loginSerial.Unlock()
+
+	loginMu.Lock()
+	delete(inFlightLogins, clusterName)
+	loginMu.Unlock()
+	close(call.done)
+
+	return call.stdout, call.stderr, call.err
+}
@@ -0,0 +1,80 @@
+package utils
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// waitForQueuedLogins waits until the given numbers of logins are in flight and callers wait for them.
+func waitForQueuedLogins(t *testing.T, logins, waiters int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		loginMu.Lock()
+		n := 0
+		for _, call := range inFlightLogins {
+			n += call.waiters
+		}
+		inFlight := len(inFlightLogins)
+		loginMu.Unlock()
+		if inFlight == logins && n == waiters {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for callers to queue: %d logins in flight, %d waiters", inFlight, n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestLoginToKubeClusterCoalescesConcurrentLogins(t *testing.T) {
+	var calls, running, maxRunning int32
+	release := make(chan struct{})
+	tshKubeLoginFunc = func(clusterName string) (string, string, error) {
+		atomic.AddInt32(&calls, 1)
+		if n := atomic.AddInt32(&running, 1); n > atomic.LoadInt32(&maxRunning) {
+			atomic.StoreInt32(&maxRunning, n)
+		}
+		<-release
+		atomic.AddInt32(&running, -1)
+		return "logged into " + clusterName, "", nil
+	}
+	defer func() { tshKubeLoginFunc = runTshKubeLogin }()
+
+	var wg sync.WaitGroup
+	results := make([]string, 10)
+	for i := range results {
+		cluster := "alpha"
+		if i%2 == 1 {
+			cluster = "beta"
+		}
+		wg.Add(1)
+		go func(i int, cluster string) {
+			defer wg.Done()
+			results[i], _, _ = LoginToKubeCluster(cluster)
+		}(i, cluster)
+	}
+
+	// Wait until every caller has either started a login or joined one, then let the logins finish.
+	waitForQueuedLogins(t, 2, len(results)-2)
+	close(release)
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Errorf("expected 2 tsh logins (one per cluster), got %d", got)
+	}
+	if got := atomic.LoadInt32(&maxRunning); got != 1 {
+		t.Errorf("expected logins to be serialized, but %d ran concurrently", got)
+	}
+	for i, r := range results {
+		want := "logged into alpha"
+		if i%2 == 1 {
+			want = "logged into beta"
+		}
+		if r != want {
+			t.Errorf("caller %d: expected %q, got %q", i, want, r)
+		}
+	}
+}
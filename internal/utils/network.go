@@ -0,0 +1,94 @@
+package utils
+
+import (
+	"net"
+	"sort"
+	"strings"
+)
+
+// virtualInterfacePrefixes are name prefixes of interfaces created by container runtimes and hypervisors
+// on the host itself. They come and go with containers and VMs and say nothing about the host's network.
+// VPN interfaces (tun, utun, wg, ppp) are deliberately not listed.
+var virtualInterfacePrefixes = []string{
+	"docker", "br-", "veth", "virbr", "vboxnet", "vmnet", "cni", "flannel", "cali", "lxc", "lxd", "podman", "kube-", "vEthernet",
+}
+
+// interfaceAddrs is an interface with its addresses, as considered by the fingerprint.
+type interfaceAddrs struct {
+	name  string
+	flags net.Flags
+	addrs []net.Addr
+}
+
+// NetworkFingerprint summarizes the host's active network configuration as a string:
+// the addresses of all physical and VPN interfaces that are up, see networkFingerprint.
+// It changes when the machine joins another network, a VPN comes up or goes down,
+// or an interface loses its address, which is what envctl needs to notice to reconnect.
+func NetworkFingerprint() (string, error) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return "", err
+	}
+	var all []interfaceAddrs
+	for _, iface := range ifaces {
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+		all = append(all, interfaceAddrs{name: iface.Name, flags: iface.Flags, addrs: addrs})
+	}
+	return networkFingerprint(all), nil
+}
+
+// networkFingerprint builds the fingerprint from interfaces that are up. It leaves out what changes
+// without the host moving to another network: loopback and virtual interfaces (virtualInterfacePrefixes),
+// link-local addresses, and the interface identifier of IPv6 addresses, which rotates with privacy
+// extensions; only the /64 prefix of an IPv6 address is used.
+func networkFingerprint(ifaces []interfaceAddrs) string {
+	var parts []string
+	for _, iface := range ifaces {
+		if iface.flags&net.FlagUp == 0 || iface.flags&net.FlagLoopback != 0 || isVirtualInterface(iface.name) {
+			continue
+		}
+		for _, addr := range iface.addrs {
+			ipNet, ok := addr.(*net.IPNet)
+			if !ok {
+				continue
+			}
+			ip := ipNet.IP
+			if ip.IsLinkLocalUnicast() || ip.IsLoopback() {
+				continue
+			}
+			part := ip.String()
+			if ip.To4() == nil {
+				part = (&net.IPNet{IP: ip.Mask(net.CIDRMask(64, 128)), Mask: net.CIDRMask(64, 128)}).String()
+			}
+			parts = append(parts, iface.name+"="+part)
+		}
+	}
+	sort.Strings(parts)
+	// Several IPv6 addresses of one interface usually share their prefix.
+	parts = dedupeSorted(parts)
+	return strings.Join(parts, ",")
+}
+
+// isVirtualInterface reports whether the interface belongs to a container runtime or hypervisor on the host.
+func isVirtualInterface(name string) bool {
+	for _, prefix := range virtualInterfacePrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// dedupeSorted removes adjacent duplicates from a sorted slice.
+func dedupeSorted(s []string) []string {
+	out := s[:0]
+	for i, v := range s {
+		if i == 0 || v != s[i-1] {
+			out = append(out, v)
+		}
+	}
+	return out
+}
@@ -0,0 +1,54 @@
+package utils
+
+import (
+	"net"
+	"testing"
+)
+
+func testIface(name string, cidrs ...string) interfaceAddrs {
+	iface := interfaceAddrs{name: name, flags: net.FlagUp}
+	for _, c := range cidrs {
+		ip, ipNet, err := net.ParseCIDR(c)
+		if err != nil {
+			panic(err)
+		}
+		ipNet.IP = ip
+		iface.addrs = append(iface.addrs, ipNet)
+	}
+	return iface
+}
+
+func TestNetworkFingerprint(t *testing.T) {
+	base := []interfaceAddrs{
+		testIface("lo", "127.0.0.1/8", "::1/128"),
+		testIface("eth0", "192.168.1.20/24", "fe80::1/64", "2001:db8:1:2::aaaa/64"),
+	}
+	base[0].flags |= net.FlagLoopback
+	want := networkFingerprint(base)
+	if want != "eth0=192.168.1.20,eth0=2001:db8:1:2::/64" {
+		t.Fatalf("unexpected fingerprint %q", want)
+	}
+
+	unchanged := map[string][]interfaceAddrs{
+		"container started": append(append([]interfaceAddrs{}, base...),
+			testIface("docker0", "172.17.0.1/16"), testIface("veth1a2b3c", "fe80::2/64"), testIface("br-0123abcd", "172.18.0.1/16")),
+		"IPv6 privacy address rotated": {base[0], testIface("eth0", "192.168.1.20/24", "fe80::1/64", "2001:db8:1:2::bbbb/64", "2001:db8:1:2::aaaa/64")},
+		"link-local address changed":   {base[0], testIface("eth0", "192.168.1.20/24", "fe80::9/64", "2001:db8:1:2::aaaa/64")},
+	}
+	for name, ifaces := range unchanged {
+		if got := networkFingerprint(ifaces); got != want {
+			t.Errorf("%s: fingerprint changed to %q", name, got)
+		}
+	}
+
+	changed := map[string][]interfaceAddrs{
+		"joined another network": {base[0], testIface("eth0", "10.0.0.5/24", "2001:db8:9:9::aaaa/64")},
+		"VPN came up":            append(append([]interfaceAddrs{}, base...), testIface("tun0", "10.8.0.2/24")),
+		"interface went down":    {base[0], {name: "eth0", addrs: base[1].addrs}},
+	}
+	for name, ifaces := range changed {
+		if got := networkFingerprint(ifaces); got == want {
+			t.Errorf("%s: expected the fingerprint to change", name)
+		}
+	}
+}
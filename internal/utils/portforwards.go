@@ -0,0 +1,94 @@
+package utils
+
+import "fmt"
+
+// ClusterRole is the role a cluster plays in a connection. Port-forward templates are instantiated
+// for every cluster that is assigned their role.
+type ClusterRole string
+
+const (
+	// RoleManagement is assigned to the management cluster of a connection.
+	RoleManagement ClusterRole = "management"
+	// RoleObservability is assigned to the cluster whose metrics are collected: the workload cluster
+	// if the connection has one, otherwise the management cluster.
+	RoleObservability ClusterRole = "observability"
+)
+
+// PortForwardTemplate describes a standard port-forward that is set up for every cluster with a given role.
+type PortForwardTemplate struct {
+	Name       string      // Service name shown in labels, e.g. "Grafana".
+	Role       ClusterRole // Role of the cluster the port-forward targets.
+	Namespace  string
+	Service    string // Target resource, e.g. "service/grafana".
+	LocalPort  string
+	RemotePort string
+}
+
+// DefaultPortForwardTemplates are the port-forwards envctl sets up for every connection.
+var DefaultPortForwardTemplates = []PortForwardTemplate{
+	{Name: "Prometheus", Role: RoleManagement, Namespace: "mimir", Service: "service/mimir-query-frontend", LocalPort: "8080", RemotePort: "8080"},
+	{Name: "Grafana", Role: RoleManagement, Namespace: "monitoring", Service: "service/grafana", LocalPort: "3000", RemotePort: "3000"},
+	{Name: "Alloy Metrics", Role: RoleObservability, Namespace: "kube-system", Service: "service/alloy-metrics-cluster", LocalPort: "12345", RemotePort: "12345"},
+}
+
+// PortForwardSpec is a port-forward instantiated from a template for a specific cluster.
+type PortForwardSpec struct {
+	Label      string // e.g. "Grafana (MC)".
+	Context    string // Kubernetes context of the target cluster.
+	IsWC       bool   // True if the port-forward targets the workload cluster.
+	Namespace  string
+	Service    string
+	LocalPort  string
+	RemotePort string
+}
+
+// PortSpec returns the "local:remote" port mapping expected by StartPortForwardClientGo.
+func (s PortForwardSpec) PortSpec() string {
+	return s.LocalPort + ":" + s.RemotePort
+}
+
+// PortForwardsForConnection instantiates templates for the clusters of a connection, in template order.
+// Roles are assigned as described on RoleManagement and RoleObservability; templates whose role is not
+// assigned to any cluster (e.g. without a management cluster) are skipped. A template is also skipped if
+// an earlier one already produced the same label or local port, so overlapping templates cannot collide.
+// - mcContext: Kubernetes context of the management cluster; empty if there is none.
+// - wcContext: Kubernetes context of the workload cluster; empty if the connection has none.
+func PortForwardsForConnection(mcContext, wcContext string, templates []PortForwardTemplate) []PortForwardSpec {
+	var specs []PortForwardSpec
+	labels := make(map[string]bool)
+	ports := make(map[string]bool)
+	for _, t := range templates {
+		context, isWC := "", false
+		switch t.Role {
+		case RoleManagement:
+			context = mcContext
+		case RoleObservability:
+			context = mcContext
+			if wcContext != "" {
+				context, isWC = wcContext, true
+			}
+		}
+		if context == "" {
+			continue
+		}
+		suffix := "MC"
+		if isWC {
+			suffix = "WC"
+		}
+		label := fmt.Sprintf("%s (%s)", t.Name, suffix)
+		if labels[label] || ports[t.LocalPort] {
+			continue
+		}
+		labels[label], ports[t.LocalPort] = true, true
+		specs = append(specs, PortForwardSpec{
+			Label:      label,
+			Context:    context,
+			IsWC:       isWC,
+			Namespace:  t.Namespace,
+			Service:    t.Service,
+			LocalPort:  t.LocalPort,
+			RemotePort: t.RemotePort,
+		})
+	}
+	return specs
+}
@@ -0,0 +1,43 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPortForwardsForConnection(t *testing.T) {
+	labels := func(specs []PortForwardSpec) []string {
+		var out []string
+		for _, s := range specs {
+			out = append(out, s.Label+"@"+s.Context)
+		}
+		return out
+	}
+
+	got := labels(PortForwardsForConnection("ctx-mc", "ctx-wc", DefaultPortForwardTemplates))
+	want := []string{"Prometheus (MC)@ctx-mc", "Grafana (MC)@ctx-mc", "Alloy Metrics (WC)@ctx-wc"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MC+WC: got %v, want %v", got, want)
+	}
+
+	// Without a workload cluster, the management cluster takes the observability role.
+	got = labels(PortForwardsForConnection("ctx-mc", "", DefaultPortForwardTemplates))
+	want = []string{"Prometheus (MC)@ctx-mc", "Grafana (MC)@ctx-mc", "Alloy Metrics (MC)@ctx-mc"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MC only: got %v, want %v", got, want)
+	}
+
+	// Templates colliding on label or local port are instantiated once.
+	templates := append([]PortForwardTemplate{}, DefaultPortForwardTemplates...)
+	templates = append(templates,
+		PortForwardTemplate{Name: "Grafana", Role: RoleManagement, LocalPort: "3001", RemotePort: "3000"},
+		PortForwardTemplate{Name: "Loki", Role: RoleManagement, LocalPort: "3000", RemotePort: "3100"},
+	)
+	if n := len(PortForwardsForConnection("ctx-mc", "", templates)); n != 3 {
+		t.Errorf("expected duplicates to be skipped, got %d port-forwards", n)
+	}
+
+	if specs := PortForwardsForConnection("", "", DefaultPortForwardTemplates); len(specs) != 0 {
+		t.Errorf("expected no port-forwards without clusters, got %v", labels(specs))
+	}
+}
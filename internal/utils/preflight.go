@@ -0,0 +1,76 @@
+package utils
+
+import (
+	"fmt"
+	"net"
+	"os"
+)
+
+// Estimates used by the preflight checks. A client-go port-forward holds a listener, the API server
+// connection and one stream pair per local connection; the base covers envctl itself, tsh and kubectl.
+const (
+	preflightBaseFDs           = 64
+	preflightFDsPerPortForward = 32
+	preflightMinFreeDiskBytes  = 10 << 20 // kubeconfig, recent clusters and telemetry spool
+)
+
+// PreflightFinding is a problem found before starting. Errors make startup fail; warnings are only printed.
+type PreflightFinding struct {
+	Error   bool   // True if envctl should refuse to start.
+	Check   string // "ports", "file descriptors" or "disk".
+	Message string
+	Remedy  string // Suggested fix.
+}
+
+// String formats the finding for the console, e.g. "ERROR ports: local port 3000 for Grafana (MC) is not available: .... Stop ...".
+func (f PreflightFinding) String() string {
+	level := "WARNING"
+	if f.Error {
+		level = "ERROR"
+	}
+	return fmt.Sprintf("%s %s: %s. %s", level, f.Check, f.Message, f.Remedy)
+}
+
+// RunPreflight estimates the resources the given port-forwards need and compares them with what is available:
+// each local port must be free on its bind addresses, the open file limit must cover the estimated descriptors, and
+// the config directory needs a little free space. Checks that are not supported on the platform are skipped.
+func RunPreflight(specs []PortForwardSpec) []PreflightFinding {
+	var findings []PreflightFinding
+
+	for _, spec := range specs {
+		for _, address := range BindAddressesFor(spec.Label) {
+			ln, err := net.Listen("tcp", net.JoinHostPort(address, spec.LocalPort))
+			if err != nil {
+				findings = append(findings, PreflightFinding{
+					Error:   true,
+					Check:   "ports",
+					Message: fmt.Sprintf("local port %s for %s is not available: %v", spec.LocalPort, spec.Label, err),
+					Remedy:  fmt.Sprintf("Stop the process using it (e.g. 'lsof -i :%s') or another envctl instance (see --leader-election)", spec.LocalPort),
+				})
+				break
+			}
+			_ = ln.Close()
+		}
+	}
+
+	required := uint64(preflightBaseFDs + preflightFDsPerPortForward*len(specs))
+	if limit, ok := openFileLimit(); ok && limit < required {
+		findings = append(findings, PreflightFinding{
+			Error:   limit < required/2,
+			Check:   "file descriptors",
+			Message: fmt.Sprintf("open file limit is %d, but about %d descriptors are needed for %d port-forwards", limit, required, len(specs)),
+			Remedy:  fmt.Sprintf("Raise the limit, e.g. 'ulimit -n %d'", 4*required),
+		})
+	}
+
+	if dir, err := os.UserConfigDir(); err == nil {
+		if free, ok := freeDiskBytes(dir); ok && free < preflightMinFreeDiskBytes {
+			findings = append(findings, PreflightFinding{
+				Check:   "disk",
+				Message: fmt.Sprintf("only %d KiB free in %s", free>>10, dir),
+				Remedy:  "Free some disk space; envctl stores kubeconfig contexts and its state there",
+			})
+		}
+	}
+	return findings
+}
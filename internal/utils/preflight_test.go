@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"net"
+	"strings"
+	"testing"
+)
+
+func TestRunPreflightBusyPort(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	_, busy, _ := net.SplitHostPort(ln.Addr().String())
+
+	free, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, freePort, _ := net.SplitHostPort(free.Addr().String())
+	free.Close()
+
+	findings := RunPreflight([]PortForwardSpec{
+		{Label: "busy", LocalPort: busy},
+		{Label: "free", LocalPort: freePort},
+	})
+	var portErrors []PreflightFinding
+	for _, f := range findings {
+		if f.Check == "ports" {
+			portErrors = append(portErrors, f)
+		}
+	}
+	if len(portErrors) != 1 || !portErrors[0].Error {
+		t.Fatalf("expected one port error, got %v", findings)
+	}
+	if !strings.HasPrefix(portErrors[0].Message, "local port "+busy+" for busy") {
+		t.Errorf("unexpected message %q", portErrors[0].Message)
+	}
+}
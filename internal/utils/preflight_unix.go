@@ -0,0 +1,23 @@
+//go:build !windows
+
+package utils
+
+import "syscall"
+
+// openFileLimit returns the soft limit on open file descriptors of the envctl process.
+func openFileLimit() (uint64, bool) {
+	var rlim syscall.Rlimit
+	if err := syscall.Getrlimit(syscall.RLIMIT_NOFILE, &rlim); err != nil {
+		return 0, false
+	}
+	return uint64(rlim.Cur), true
+}
+
+// freeDiskBytes returns the space available to unprivileged users on the file system containing path.
+func freeDiskBytes(path string) (uint64, bool) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return 0, false
+	}
+	return uint64(st.Bavail) * uint64(st.Bsize), true
+}
@@ -0,0 +1,13 @@
+//go:build windows
+
+package utils
+
+// openFileLimit is not checked on Windows, where handles are not limited per process like Unix descriptors.
+func openFileLimit() (uint64, bool) {
+	return 0, false
+}
+
+// freeDiskBytes is not checked on Windows.
+func freeDiskBytes(path string) (uint64, bool) {
+	return 0, false
+}
@@ -0,0 +1,112 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/http/httpproxy"
+	"k8s.io/client-go/rest"
+)
+
+// ProxyError marks an error that occurred while connecting through an HTTP(S) proxy,
+// so it can be reported distinctly from failures of the destination itself.
+type ProxyError struct {
+	Proxy string // The proxy URL, with credentials redacted.
+	Err   error  // The underlying error.
+}
+
+func (e *ProxyError) Error() string {
+	return fmt.Sprintf("proxy error via %s: %v", e.Proxy, e.Err)
+}
+
+func (e *ProxyError) Unwrap() error {
+	return e.Err
+}
+
+// IsProxyError reports whether err (or any error it wraps) is a ProxyError.
+func IsProxyError(err error) bool {
+	var proxyErr *ProxyError
+	return errors.As(err, &proxyErr)
+}
+
+// ProxyForURL returns the proxy that outbound HTTP(S) requests to target go through,
+// following HTTP_PROXY, HTTPS_PROXY and NO_PROXY like the Go HTTP client, client-go and self-update do.
+// The environment is read on every call. Credentials in the proxy URL are redacted.
+// Returns an empty string for direct connections.
+// - target: The destination URL, e.g. a Kubernetes API server or https://api.github.com.
+func ProxyForURL(target string) string {
+	u, err := url.Parse(target)
+	if err != nil || u.Host == "" {
+		return ""
+	}
+	proxyURL, err := httpproxy.FromEnvironment().ProxyFunc()(u)
+	if err != nil || proxyURL == nil {
+		return ""
+	}
+	return proxyURL.Redacted()
+}
+
+// ProxyForRestConfig returns the proxy that client-go uses for requests to the cluster of restConfig:
+// the kubeconfig's proxy-url if set (restConfig.Proxy), otherwise the environment as in ProxyForURL.
+// Credentials in the proxy URL are redacted. Returns an empty string for direct connections.
+func ProxyForRestConfig(restConfig *rest.Config) string {
+	if restConfig == nil {
+		return ""
+	}
+	if restConfig.Proxy == nil {
+		return ProxyForURL(restConfig.Host)
+	}
+	u, err := url.Parse(restConfig.Host)
+	if err != nil || u.Host == "" {
+		return ""
+	}
+	proxyURL, err := restConfig.Proxy(&http.Request{URL: u})
+	if err != nil || proxyURL == nil {
+		return ""
+	}
+	return proxyURL.Redacted()
+}
+
+// proxyFailureMarkers are substrings of errors that originate from the proxy rather than the destination.
+var proxyFailureMarkers = []string{
+	"proxyconnect",                  // net/http failed to connect to the proxy
+	"Proxy Authentication Required", // HTTP 407 from the proxy
+	"proxy: ",                       // errors returned by CONNECT tunnelling
+}
+
+// AnnotateProxyError wraps err in a ProxyError if the request to target went through a proxy
+// and the error was caused by that proxy. Other errors are returned unchanged.
+// - err: The error returned by the outbound request.
+// - target: The destination URL of the request.
+func AnnotateProxyError(err error, target string) error {
+	if err == nil || IsProxyError(err) {
+		return err
+	}
+	return annotateProxyError(err, ProxyForURL(target))
+}
+
+// AnnotateKubeProxyError is AnnotateProxyError for requests made by client-go with restConfig,
+// taking the kubeconfig's proxy-url into account (see ProxyForRestConfig).
+func AnnotateKubeProxyError(err error, restConfig *rest.Config) error {
+	if err == nil || IsProxyError(err) {
+		return err
+	}
+	return annotateProxyError(err, ProxyForRestConfig(restConfig))
+}
+
+// annotateProxyError wraps err in a ProxyError if a proxy was used and the error was caused by it.
+func annotateProxyError(err error, proxy string) error {
+	if proxy == "" {
+		return err
+	}
+	msg := err.Error()
+	for _, marker := range proxyFailureMarkers {
+		if strings.Contains(msg, marker) {
+			return &ProxyError{Proxy: proxy, Err: err}
+		}
+	}
+	return err
+}
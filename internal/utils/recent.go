@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// recentClustersPath returns the file storing when each cluster was last connected to by envctl.
+func recentClustersPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine user config directory: %w", err)
+	}
+	return filepath.Join(dir, "envctl", "recent-clusters.json"), nil
+}
+
+// LoadRecentClusters returns the last time envctl connected to each cluster, keyed by full cluster name
+// (e.g. "myinstallation" or "myinstallation-myworkloadcluster").
+// A missing file yields an empty map without error.
+func LoadRecentClusters() (map[string]time.Time, error) {
+	recent := make(map[string]time.Time)
+	path, err := recentClustersPath()
+	if err != nil {
+		return recent, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return recent, nil
+	}
+	if err != nil {
+		return recent, fmt.Errorf("failed to read recent clusters %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, &recent); err != nil {
+		return make(map[string]time.Time), fmt.Errorf("malformed recent clusters file %s: %w", path, err)
+	}
+	return recent, nil
+}
+
+// RecordClusterUsage stores the current time as the last-used timestamp for the given clusters.
+// Empty names are ignored.
+// - clusterNames: Full cluster names that were just connected to.
+func RecordClusterUsage(clusterNames ...string) error {
+	path, err := recentClustersPath()
+	if err != nil {
+		return err
+	}
+	recent, _ := LoadRecentClusters() // A malformed file is simply overwritten.
+	now := time.Now()
+	for _, name := range clusterNames {
+		if name != "" {
+			recent[name] = now
+		}
+	}
+	data, err := json.MarshalIndent(recent, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode recent clusters: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", path, err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write recent clusters %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,216 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// maxTelemetrySpool bounds the number of unsent telemetry events kept on disk.
+const maxTelemetrySpool = 100
+
+// telemetrySendTimeout bounds how long sending telemetry may delay the exit of a command.
+const telemetrySendTimeout = 2 * time.Second
+
+// TelemetrySettings is the opt-in state of anonymized usage telemetry, stored in the user's config directory.
+type TelemetrySettings struct {
+	Enabled   bool   `json:"enabled"`
+	Endpoint  string `json:"endpoint"`  // URL events are POSTed to as a JSON array.
+	InstallID string `json:"installId"` // Random ID generated at opt-in; not derived from the user or host.
+}
+
+// TelemetryEvent is everything telemetry sends about one command run. It never contains arguments,
+// flag values, cluster names or error messages.
+type TelemetryEvent struct {
+	InstallID  string    `json:"installId"`
+	Version    string    `json:"version"`
+	OS         string    `json:"os"`
+	Arch       string    `json:"arch"`
+	Command    string    `json:"command"`    // e.g. "envctl connect".
+	Flags      []string  `json:"flags"`      // Names of the flags that were set, e.g. ["no-tui"].
+	ErrorClass string    `json:"errorClass"` // See ClassifyError.
+	Hour       time.Time `json:"hour"`       // When the command ran, truncated to the hour.
+}
+
+// telemetryPath returns a file in envctl's directory in the user's config directory.
+func telemetryPath(name string) (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine user config directory: %w", err)
+	}
+	return filepath.Join(dir, "envctl", name), nil
+}
+
+// readTelemetryFile decodes a telemetry JSON file into v. A missing file leaves v unchanged without error.
+func readTelemetryFile(name string, v any) error {
+	path, err := telemetryPath(name)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("malformed telemetry file %s: %w", path, err)
+	}
+	return nil
+}
+
+// writeTelemetryFile encodes v into a telemetry JSON file.
+func writeTelemetryFile(name string, v any) error {
+	path, err := telemetryPath(name)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", path, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", path, err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// LoadTelemetrySettings returns the telemetry settings. Telemetry is disabled unless the user opted in.
+func LoadTelemetrySettings() (TelemetrySettings, error) {
+	var settings TelemetrySettings
+	err := readTelemetryFile("telemetry.json", &settings)
+	return settings, err
+}
+
+// EnableTelemetry opts in to telemetry sent to the given endpoint, keeping the install ID of an earlier opt-in.
+func EnableTelemetry(endpoint string) (TelemetrySettings, error) {
+	if !strings.HasPrefix(endpoint, "https://") && !strings.HasPrefix(endpoint, "http://") {
+		return TelemetrySettings{}, fmt.Errorf("invalid telemetry endpoint %q: expected an http(s) URL", endpoint)
+	}
+	settings, _ := LoadTelemetrySettings() // A malformed file is simply overwritten.
+	if settings.InstallID == "" {
+		id := make([]byte, 8)
+		if _, err := rand.Read(id); err != nil {
+			return TelemetrySettings{}, fmt.Errorf("failed to generate install ID: %w", err)
+		}
+		settings.InstallID = hex.EncodeToString(id)
+	}
+	settings.Enabled = true
+	settings.Endpoint = endpoint
+	return settings, writeTelemetryFile("telemetry.json", settings)
+}
+
+// DisableTelemetry opts out of telemetry and discards unsent events.
+func DisableTelemetry() error {
+	if err := writeTelemetryFile("telemetry.json", TelemetrySettings{}); err != nil {
+		return err
+	}
+	return writeTelemetryFile("telemetry-spool.json", []TelemetryEvent{})
+}
+
+// PendingTelemetryEvents returns the recorded events that have not been sent yet.
+func PendingTelemetryEvents() ([]TelemetryEvent, error) {
+	var events []TelemetryEvent
+	err := readTelemetryFile("telemetry-spool.json", &events)
+	return events, err
+}
+
+// NewTelemetryEvent builds the event for a command run.
+// - command: The command path, e.g. "envctl connect".
+// - flags: Names of the flags that were set; their values are never included.
+// - err: The error the command returned, reduced to its class.
+func NewTelemetryEvent(settings TelemetrySettings, version, command string, flags []string, err error) TelemetryEvent {
+	if flags == nil {
+		flags = []string{}
+	}
+	return TelemetryEvent{
+		InstallID:  settings.InstallID,
+		Version:    version,
+		OS:         runtime.GOOS,
+		Arch:       runtime.GOARCH,
+		Command:    command,
+		Flags:      flags,
+		ErrorClass: ClassifyError(err),
+		Hour:       time.Now().UTC().Truncate(time.Hour),
+	}
+}
+
+// ClassifyError reduces an error to a coarse class suitable for telemetry: "none", "proxy", "login",
+// "kubernetes" or "other". The error message itself is never sent.
+func ClassifyError(err error) string {
+	msg := ""
+	if err != nil {
+		msg = strings.ToLower(err.Error())
+	}
+	switch {
+	case err == nil:
+		return "none"
+	case IsProxyError(err):
+		return "proxy"
+	case strings.Contains(msg, "tsh") || strings.Contains(msg, "log into"):
+		return "login"
+	case strings.Contains(msg, "kubectl") || strings.Contains(msg, "context"):
+		return "kubernetes"
+	default:
+		return "other"
+	}
+}
+
+// RecordTelemetryEvent queues an event and tries to send all queued events. It does nothing unless the user
+// opted in. Events that cannot be sent stay queued for the next run, up to maxTelemetrySpool.
+func RecordTelemetryEvent(settings TelemetrySettings, event TelemetryEvent) error {
+	if !settings.Enabled || settings.Endpoint == "" {
+		return nil
+	}
+	events, _ := PendingTelemetryEvents() // A malformed spool is simply replaced.
+	events = append(events, event)
+	if len(events) > maxTelemetrySpool {
+		events = events[len(events)-maxTelemetrySpool:]
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), telemetrySendTimeout)
+	defer cancel()
+	if err := sendTelemetry(ctx, settings.Endpoint, events); err != nil {
+		if writeErr := writeTelemetryFile("telemetry-spool.json", events); writeErr != nil {
+			return writeErr
+		}
+		return err
+	}
+	return writeTelemetryFile("telemetry-spool.json", []TelemetryEvent{})
+}
+
+// sendTelemetry POSTs events as a JSON array. The default transport honours HTTPS_PROXY/NO_PROXY.
+func sendTelemetry(ctx context.Context, endpoint string, events []TelemetryEvent) error {
+	body, err := json.Marshal(events)
+	if err != nil {
+		return fmt.Errorf("failed to encode telemetry: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create telemetry request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send telemetry: %w", AnnotateProxyError(err, endpoint))
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("telemetry endpoint %s returned %s", endpoint, resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClassifyError(t *testing.T) {
+	for _, tt := range []struct {
+		err  error
+		want string
+	}{
+		{nil, "none"},
+		{&ProxyError{Proxy: "http://proxy:3128", Err: errors.New("refused")}, "proxy"},
+		{errors.New("failed to log into management cluster 'alpha': exit status 1"), "login"},
+		{errors.New("failed to switch kubectl context"), "kubernetes"},
+		{errors.New("boom"), "other"},
+	} {
+		if got := ClassifyError(tt.err); got != tt.want {
+			t.Errorf("ClassifyError(%v) = %q, want %q", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestRecordTelemetryEvent(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	var received []TelemetryEvent
+	status := http.StatusServiceUnavailable
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var events []TelemetryEvent
+		_ = json.NewDecoder(r.Body).Decode(&events)
+		if status == http.StatusOK {
+			received = events
+		}
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	// Nothing is recorded without opting in.
+	event := NewTelemetryEvent(TelemetrySettings{}, "1.0.0", "envctl connect", nil, errors.New("failed to log into alpha"))
+	if err := RecordTelemetryEvent(TelemetrySettings{}, event); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pending, _ := PendingTelemetryEvents(); len(pending) != 0 {
+		t.Fatalf("expected nothing to be recorded while disabled, got %v", pending)
+	}
+
+	settings, err := EnableTelemetry(server.URL)
+	if err != nil || settings.InstallID == "" {
+		t.Fatalf("EnableTelemetry() = %+v, %v", settings, err)
+	}
+
+	// Unsent events are kept for the next run.
+	if err := RecordTelemetryEvent(settings, event); err == nil {
+		t.Fatal("expected the unavailable endpoint to be reported")
+	}
+	if pending, _ := PendingTelemetryEvents(); len(pending) != 1 {
+		t.Fatalf("expected 1 pending event, got %d", len(pending))
+	}
+
+	status = http.StatusOK
+	if err := RecordTelemetryEvent(settings, event); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(received) != 2 || received[0].ErrorClass != "login" || received[0].Command != "envctl connect" {
+		t.Fatalf("unexpected events sent: %+v", received)
+	}
+	if pending, _ := PendingTelemetryEvents(); len(pending) != 0 {
+		t.Fatalf("expected the spool to be cleared, got %d events", len(pending))
+	}
+}
@@ -21,6 +21,7 @@ type ClusterInfo struct {
 // It returns a pointer to the populated ClusterInfo struct and an error if `tsh kube ls` fails or parsing encounters issues.
 func GetClusterInfo() (*ClusterInfo, error) {
 	cmd := exec.Command("tsh", "kube", "ls")
+	cmd.Env = ChildEnv("tsh")
 	var out bytes.Buffer
 	var stderr bytes.Buffer
 	cmd.Stdout = &out